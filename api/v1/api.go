@@ -2,7 +2,7 @@
 package v1
 
 import (
-	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
 
@@ -10,6 +10,7 @@ import (
 	"smuggr.xyz/goptivum/api/v1/middleware"
 	"smuggr.xyz/goptivum/common/config"
 	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/sse"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
@@ -19,27 +20,33 @@ import (
 var DefaultRouter *gin.Engine
 var Config *config.APIConfig
 
-func Initialize(scheduleChannels *models.ScheduleChannels) chan error {
-	fmt.Println("initializing api/v1")
+// Origins allowed by CORS, and to open WebSockets from the browser
+var AllowedOrigins = []string{"http://localhost:3000", "http://localhost:3002", "http://localhost:3001", "https://zsem.smuggr.xyz"}
+
+func Initialize() chan error {
+	slog.Info("initializing api/v1")
 
 	Config = &config.Global.API
 	gin.SetMode(os.Getenv("GIN_MODE"))
 
 	DefaultRouter = gin.Default()
 	DefaultRouter.Use(middleware.NormalizeTrailingSlashMiddleware())
+	DefaultRouter.Use(middleware.MetricsMiddleware())
 	// DefaultRouter.UseRawPath = true
 	// DefaultRouter.RedirectTrailingSlash = false
 
 	DefaultRouter.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3002", "http://localhost:3001", "https://zsem.smuggr.xyz"},
+		AllowOrigins:     AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "X-Auth-Token"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}))
 
+	sse.AllowedOrigins = AllowedOrigins
+
 	DefaultRouter.Use(gzip.Gzip(gzip.DefaultCompression))
-	routes.Initialize(DefaultRouter, scheduleChannels, &models.OtherChannels{
+	routes.Initialize(DefaultRouter, &models.OtherChannels{
 		Clients: make(chan int64),
 	})
 
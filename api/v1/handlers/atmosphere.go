@@ -4,6 +4,7 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
@@ -118,6 +119,7 @@ func WeatherForecastHandler(c *gin.Context) {
 	// #nosec G107
 	resp, err := http.Get(url)
 	if err != nil {
+		OpenWeatherStatus.Record(err)
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to fetch forecast data",
 			Success: false,
@@ -127,6 +129,7 @@ func WeatherForecastHandler(c *gin.Context) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		OpenWeatherStatus.Record(fmt.Errorf("OpenWeather returned status: %d", resp.StatusCode))
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to fetch forecast data",
 			Success: false,
@@ -135,12 +138,14 @@ func WeatherForecastHandler(c *gin.Context) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&openWeatherData); err != nil {
+		OpenWeatherStatus.Record(err)
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to parse forecast data",
 			Success: false,
 		})
 		return
 	}
+	OpenWeatherStatus.Record(nil)
 
 	now := time.Now().UTC()
 	forecastResponse := &models.ForecastResponse{
@@ -195,6 +200,7 @@ func CurrentWeatherHandler(c *gin.Context) {
 	// #nosec G107 - URL is constructed from trusted configuration and validated
 	resp, err := http.Get(url)
 	if err != nil {
+		OpenWeatherStatus.Record(err)
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to fetch current weather data",
 			Success: false,
@@ -204,6 +210,7 @@ func CurrentWeatherHandler(c *gin.Context) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		OpenWeatherStatus.Record(fmt.Errorf("OpenWeather returned status: %d", resp.StatusCode))
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to fetch current weather data",
 			Success: false,
@@ -213,12 +220,14 @@ func CurrentWeatherHandler(c *gin.Context) {
 
 	var openWeatherData map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&openWeatherData); err != nil {
+		OpenWeatherStatus.Record(err)
 		Respond(c, http.StatusInternalServerError, models.APIResponse{
 			Message: "failed to parse current weather data",
 			Success: false,
 		})
 		return
 	}
+	OpenWeatherStatus.Record(nil)
 
 	condition := openWeatherData["weather"].([]interface{})[0].(map[string]interface{})
 	temperature := openWeatherData["main"].(map[string]interface{})
@@ -249,13 +258,15 @@ func CurrentAirPollutionHandler(c *gin.Context) {
 
     if Config.UseLocalWeatherStation {
         airPollutionResponse, err = fetchLocalAirPollutionData()
+        LocalWeatherStationStatus.Record(err)
         if err != nil {
-            fmt.Println("local weather station failed:", err)
+            slog.Warn("local weather station failed", "error", err)
         }
     }
 
     if airPollutionResponse == nil {
         airPollutionResponse, err = fetchOpenWeatherAirPollutionData()
+        OpenWeatherStatus.Record(err)
         if err != nil {
             Respond(c, http.StatusInternalServerError, models.APIResponse{
                 Message: "failed to fetch air pollution data from all sources",
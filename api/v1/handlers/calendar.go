@@ -0,0 +1,95 @@
+// handlers/calendar.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/calendar"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The feed is built from the datastore on every request,
+// so subscribed calendars pick up schedule changes on refresh
+func respondCalendar(c *gin.Context, name, uid string, schedule *models.Schedule) {
+	school := getSchool(c)
+	location := school.Location
+	now := time.Now().In(location)
+
+	term, err := calendar.NewTerm(school.Config.Term.Start, school.Config.Term.End, now, location)
+	if err != nil {
+		Respond(c, http.StatusInternalServerError, models.APIResponse{
+			Message: err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	scheduleCalendar := &calendar.Calendar{
+		Name:     name,
+		UID:      uid,
+		Schedule: schedule,
+		Term:     term,
+		Location: location,
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", uid+".ics"))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(scheduleCalendar.Build(now)))
+}
+
+func GetDivisionCalendarHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	division, err := getSchool(c).Store.GetDivision(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "division not found",
+			Success: false,
+		})
+		return
+	}
+
+	respondCalendar(c, fmt.Sprintf("%s %s", division.Designator, division.FullName), fmt.Sprintf("division-%d", index), division.Schedule)
+}
+
+func GetTeacherCalendarHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	teacher, err := getSchool(c).Store.GetTeacher(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "teacher not found",
+			Success: false,
+		})
+		return
+	}
+
+	respondCalendar(c, fmt.Sprintf("%s (%s)", teacher.FullName, teacher.Designator), fmt.Sprintf("teacher-%d", index), teacher.Schedule)
+}
+
+func GetRoomCalendarHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	room, err := getSchool(c).Store.GetRoom(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "room not found",
+			Success: false,
+		})
+		return
+	}
+
+	respondCalendar(c, fmt.Sprintf("%s %s", room.Designator, room.FullName), fmt.Sprintf("room-%d", index), room.Schedule)
+}
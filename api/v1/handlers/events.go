@@ -0,0 +1,245 @@
+// handlers/events.go
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/diff"
+	"smuggr.xyz/goptivum/core/scraper"
+	"smuggr.xyz/goptivum/core/sse"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// Counts the connected clients, its events are the changes of the count
+var ClientsHub *sse.Hub
+
+const PeriodsTopic = "periods"
+
+var topicResources = []scraper.ResourceType{scraper.DivisionResource, scraper.TeacherResource, scraper.RoomResource}
+
+// Payloads clients can ask for with the "payload" query parameter, the
+// updates of divisions, teachers and rooms then carry the saved item as JSON,
+// as base64 protobuf or as the diff against its previous version
+const (
+	IndexPayload    = "index"
+	JSONPayload     = "json"
+	ProtobufPayload = "protobuf"
+	DiffPayload     = "diff"
+)
+
+// Checks the payload and the WebSocket framing the client asked for
+func validStreamQuery(c *gin.Context) bool {
+	if !sse.ValidFormat(c.Query("format")) {
+		respondBadRequest(c, "invalid format")
+		return false
+	}
+
+	switch c.Query("payload") {
+	case "", IndexPayload, JSONPayload, ProtobufPayload, DiffPayload:
+		return true
+	}
+
+	respondBadRequest(c, "invalid payload")
+	return false
+}
+
+// Returns the payloads of an update of the item, each one is built from
+// the latest version of the item when the first client asking for it is sent
+// the event, so updates nobody asked payloads for cost nothing
+func (s *School) RefreshPayloads(resourceType scraper.ResourceType, index int64) sse.PayloadFunc {
+	return func(payload string) (interface{}, bool) {
+		return s.RefreshPayload(resourceType, index, payload)
+	}
+}
+
+// Encodes the latest version of the item in the payload, ok is false if it
+// can't be encoded so its clients get the index instead
+func (s *School) RefreshPayload(resourceType scraper.ResourceType, index int64, payload string) (interface{}, bool) {
+	switch payload {
+	case JSONPayload, ProtobufPayload, DiffPayload:
+	default:
+		return nil, false
+	}
+
+	log := slog.With("school", s.ID, "resource", resourceType, "index", index, "payload", payload)
+
+	history, err := s.Store.GetHistory(resourceType.String(), index)
+	if err != nil || len(history.Versions) == 0 {
+		log.Warn("error reading history for payload", "error", err)
+		return nil, false
+	}
+
+	scheduleDiff := &models.ScheduleDiff{To: history.Versions[len(history.Versions)-1]}
+	item, err := s.getVersion(resourceType, index, scheduleDiff.To)
+	if err != nil {
+		log.Warn("error reading item for payload", "error", err)
+		return nil, false
+	}
+
+	switch payload {
+	case JSONPayload:
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, false
+		}
+		return string(data), true
+
+	case ProtobufPayload:
+		data, err := proto.Marshal(item)
+		if err != nil {
+			return nil, false
+		}
+		return base64.StdEncoding.EncodeToString(data), true
+	}
+
+	var fromSchedule *models.Schedule
+	if len(history.Versions) > 1 {
+		scheduleDiff.From = history.Versions[len(history.Versions)-2]
+		previous, err := s.getVersion(resourceType, index, scheduleDiff.From)
+		if err != nil {
+			log.Warn("error reading previous item for payload", "error", err)
+			return nil, false
+		}
+		fromSchedule = previous.GetSchedule()
+	}
+
+	changes, err := diff.Schedules(fromSchedule, item.GetSchedule())
+	if err != nil {
+		log.Debug("skipping diff payload", "from", scheduleDiff.From, "error", err)
+		return nil, false
+	}
+
+	scheduleDiff.Changes = changes
+	data, err := json.Marshal(scheduleDiff)
+	if err != nil {
+		return nil, false
+	}
+
+	return string(data), true
+}
+
+// Topic of the events of a single division, teacher or room, e.g. division:3,
+// the changes of a resource's list are under the resource type alone
+func ItemTopic(resource scraper.ResourceType, index int64) string {
+	return fmt.Sprintf("%s:%d", resource, index)
+}
+
+func DivisionsEventsHandler(c *gin.Context) {
+	if !validStreamQuery(c) {
+		return
+	}
+
+	getSchool(c).DivisionsHub.Handler()(c.Writer, c.Request)
+}
+
+func TeachersEventsHandler(c *gin.Context) {
+	if !validStreamQuery(c) {
+		return
+	}
+
+	getSchool(c).TeachersHub.Handler()(c.Writer, c.Request)
+}
+
+func RoomsEventsHandler(c *gin.Context) {
+	if !validStreamQuery(c) {
+		return
+	}
+
+	getSchool(c).RoomsHub.Handler()(c.Writer, c.Request)
+}
+
+func PeriodsEventsHandler(c *gin.Context) {
+	if !validStreamQuery(c) {
+		return
+	}
+
+	getSchool(c).PeriodsHub.Handler()(c.Writer, c.Request)
+}
+
+// Values of a query parameter given several times or separated with commas
+func queryValues(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range c.QueryArray(key) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+
+	return values
+}
+
+// Streams the events of the entities given in the query, e.g.
+// ?division=3&teacher=12,14&list=room&periods=true, over one connection,
+// every event of the school if nothing is given. WebSocket clients can
+// change their topics later with subscribe and unsubscribe requests
+func EventsHandler(c *gin.Context) {
+	if !validStreamQuery(c) {
+		return
+	}
+
+	var topics []string
+	for _, resource := range topicResources {
+		for _, value := range queryValues(c, resource.String()) {
+			index, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				respondBadRequest(c, fmt.Sprintf("invalid %s index", resource))
+				return
+			}
+			topics = append(topics, ItemTopic(resource, index))
+		}
+	}
+
+	for _, value := range queryValues(c, "list") {
+		valid := false
+		for _, resource := range topicResources {
+			valid = valid || value == resource.String()
+		}
+		if !valid {
+			respondBadRequest(c, "invalid list")
+			return
+		}
+		topics = append(topics, value)
+	}
+
+	if periods, _ := strconv.ParseBool(c.Query("periods")); periods {
+		topics = append(topics, PeriodsTopic)
+	}
+
+	if err := sse.CheckTopics(topics); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	getSchool(c).EventsHub.SubscribeHandler(topics)(c.Writer, c.Request)
+}
+
+func itemEventsHandler(c *gin.Context, resource scraper.ResourceType) {
+	index, ok := parseIndexParam(c)
+	if !ok || !validStreamQuery(c) {
+		return
+	}
+
+	getSchool(c).EventsHub.SubscribeHandler([]string{ItemTopic(resource, index)})(c.Writer, c.Request)
+}
+
+func DivisionEventsHandler(c *gin.Context) {
+	itemEventsHandler(c, scraper.DivisionResource)
+}
+
+func TeacherEventsHandler(c *gin.Context) {
+	itemEventsHandler(c, scraper.TeacherResource)
+}
+
+func RoomEventsHandler(c *gin.Context) {
+	itemEventsHandler(c, scraper.RoomResource)
+}
@@ -0,0 +1,171 @@
+// handlers/events_test.go
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/scraper"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// Opens an in-memory datastore shared by the test schools
+func setupDatastore(t *testing.T) {
+	t.Helper()
+
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("error opening datastore: %v", err)
+	}
+	datastore.DB = db
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	Config = &config.APIConfig{MaxSSEClients: 10}
+}
+
+func newTestSchool(id string, keyPrefix string) *School {
+	return newSchool(scraper.NewSchool(config.SchoolConfig{
+		ID:        id,
+		KeyPrefix: keyPrefix,
+	}))
+}
+
+func divisionWithLesson(name string) *models.Division {
+	return &models.Division{
+		Index:      3,
+		Designator: "3A",
+		Schedule: &models.Schedule{
+			ScheduleDays: []*models.ScheduleDay{{
+				LessonGroups: []*models.LessonGroup{{
+					Period:  1,
+					Lessons: []*models.Lesson{{FullName: name, SubjectName: name}},
+				}},
+			}},
+		},
+	}
+}
+
+func diffPayload(t *testing.T, school *School) *models.ScheduleDiff {
+	t.Helper()
+
+	data, ok := school.RefreshPayload(scraper.DivisionResource, 3, DiffPayload)
+	if !ok {
+		t.Fatal("diff payload wasn't built")
+	}
+
+	var scheduleDiff models.ScheduleDiff
+	if err := json.Unmarshal([]byte(data.(string)), &scheduleDiff); err != nil {
+		t.Fatalf("error parsing diff payload: %v", err)
+	}
+
+	return &scheduleDiff
+}
+
+func TestRefreshPayload(t *testing.T) {
+	setupDatastore(t)
+	school := newTestSchool("test", "test:")
+
+	if _, ok := school.RefreshPayload(scraper.DivisionResource, 3, JSONPayload); ok {
+		t.Error("json payload of an item that isn't stored: got one, want none")
+	}
+
+	first := divisionWithLesson("matematyka")
+	if err := school.Store.SetDivision(first); err != nil {
+		t.Fatalf("error saving division: %v", err)
+	}
+
+	data, ok := school.RefreshPayload(scraper.DivisionResource, 3, JSONPayload)
+	var division models.Division
+	if !ok || json.Unmarshal([]byte(data.(string)), &division) != nil || division.Designator != "3A" {
+		t.Errorf("json payload: got %v, want the division", data)
+	}
+
+	data, ok = school.RefreshPayload(scraper.DivisionResource, 3, ProtobufPayload)
+	encoded, _ := base64.StdEncoding.DecodeString(data.(string))
+	if !ok || proto.Unmarshal(encoded, &division) != nil || !proto.Equal(&division, first) {
+		t.Errorf("protobuf payload: got %v, want the division", data)
+	}
+
+	if _, ok := school.RefreshPayload(scraper.DivisionResource, 3, IndexPayload); ok {
+		t.Error("index payload: got one, want the clients to get the index")
+	}
+
+	// The first version is diffed against an empty schedule
+	scheduleDiff := diffPayload(t, school)
+	if scheduleDiff.From != 0 || scheduleDiff.To == 0 {
+		t.Errorf("first diff: got versions %d to %d, want none to the first", scheduleDiff.From, scheduleDiff.To)
+	}
+	if len(scheduleDiff.Changes) != 1 || scheduleDiff.Changes[0].Type != "lesson_added" {
+		t.Errorf("first diff: got %v, want the lesson added", scheduleDiff.Changes)
+	}
+
+	// Versions are keyed by the time they're saved
+	time.Sleep(2 * time.Millisecond)
+	if err := school.Store.SetDivision(divisionWithLesson("fizyka")); err != nil {
+		t.Fatalf("error saving division: %v", err)
+	}
+
+	second := diffPayload(t, school)
+	if second.From != scheduleDiff.To || second.To <= second.From {
+		t.Errorf("second diff: got versions %d to %d, want %d to a later one", second.From, second.To, scheduleDiff.To)
+	}
+
+	changes := make(map[string]string)
+	for _, change := range second.Changes {
+		lesson := change.Lesson
+		if lesson == nil {
+			lesson = change.PreviousLesson
+		}
+		changes[lesson.FullName] = change.Type
+	}
+	if len(changes) != 2 || changes["matematyka"] != "lesson_removed" || changes["fizyka"] != "lesson_added" {
+		t.Errorf("second diff: got %v, want matematyka removed and fizyka added", second.Changes)
+	}
+
+	// Versions scraped with period numbers can't be diffed against older ones
+	withPeriods := divisionWithLesson("fizyka")
+	withPeriods.Schedule.HasPeriods = true
+	if err := school.Store.SetDivision(withPeriods); err != nil {
+		t.Fatalf("error saving division: %v", err)
+	}
+
+	if data, ok := school.RefreshPayload(scraper.DivisionResource, 3, DiffPayload); ok {
+		t.Errorf("diff payload across the period numbers: got %v, want none", data)
+	}
+}
+
+func TestEventsHandlerTopicLimit(t *testing.T) {
+	setupDatastore(t)
+	school := newTestSchool("test", "test:")
+
+	indexes := make([]string, 257)
+	for i := range indexes {
+		indexes[i] = fmt.Sprint(i)
+	}
+
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/?division="+strings.Join(indexes, ","), nil)
+	c.Set(schoolKey, school)
+
+	EventsHandler(c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -10,13 +11,13 @@ import (
 	"smuggr.xyz/goptivum/common/config"
 	"smuggr.xyz/goptivum/common/models"
 	"smuggr.xyz/goptivum/common/utils"
+	"smuggr.xyz/goptivum/core/scraper"
 
 	"github.com/gin-gonic/gin"
 	"google.golang.org/protobuf/proto"
 )
 
 var Config *config.APIConfig
-var ScraperConfig *config.ScraperConfig
 
 func Respond(c *gin.Context, code int, data interface{}) {
 	accept := c.GetHeader("Accept")
@@ -49,34 +50,37 @@ func APIHealthHandler(c *gin.Context) {
 	scraperHealthy := true
 	weatherHealthy := true
 
-	if !utils.CheckURL(ScraperConfig.BaseUrl) {
-		scraperHealthy = false
-	}
+	for _, school := range Schools {
+		scraperConfig := school.Config
+		if !utils.CheckURL(school.Client, scraperConfig.BaseUrl) {
+			scraperHealthy = false
+		}
 
-	url := fmt.Sprintf(ScraperConfig.BaseUrl + ScraperConfig.Endpoints.DivisionsList)
-	if !utils.CheckURL(url) {
-		scraperHealthy = false
-	}
+		url := fmt.Sprintf(scraperConfig.BaseUrl + scraperConfig.Endpoints.DivisionsList)
+		if !utils.CheckURL(school.Client, url) {
+			scraperHealthy = false
+		}
 
-	url = fmt.Sprintf(ScraperConfig.BaseUrl + ScraperConfig.Endpoints.TeachersList)
-	if !utils.CheckURL(url) {
-		scraperHealthy = false
-	}
+		url = fmt.Sprintf(scraperConfig.BaseUrl + scraperConfig.Endpoints.TeachersList)
+		if !utils.CheckURL(school.Client, url) {
+			scraperHealthy = false
+		}
 
-	url = fmt.Sprintf(ScraperConfig.BaseUrl + ScraperConfig.Endpoints.RoomsList)
-	if !utils.CheckURL(url) {
-		scraperHealthy = false
+		url = fmt.Sprintf(scraperConfig.BaseUrl + scraperConfig.Endpoints.RoomsList)
+		if !utils.CheckURL(school.Client, url) {
+			scraperHealthy = false
+		}
 	}
 
 	lang := c.DefaultQuery("lang", "en")
 	units := c.DefaultQuery("units", "metric")
 	apiKey := os.Getenv("OPENWEATHER_API_KEY")
-	url = fmt.Sprintf("%s%s",
+	url := fmt.Sprintf("%s%s",
 		Config.OpenWeather.BaseUrl,
 		fmt.Sprintf(Config.OpenWeather.Endpoints.ForecastWeather, Config.OpenWeather.Lat, Config.OpenWeather.Lon, apiKey, lang, units, 40),
 	)
 
-	if !utils.CheckURL(url) {
+	if !utils.CheckURL(utils.HttpClient, url) {
 		weatherHealthy = false
 	}
 
@@ -90,7 +94,17 @@ func APIHealthHandler(c *gin.Context) {
 }
 
 func Initialize() {
-	fmt.Println("initializing handlers")
+	slog.Info("initializing handlers")
 	Config = &config.Global.API
-	ScraperConfig = &config.Global.Scraper
+
+	Schools = nil
+	for _, scraperSchool := range scraper.Schools {
+		school := newSchool(scraperSchool)
+		Schools = append(Schools, school)
+		if scraperSchool == scraper.DefaultSchool {
+			DefaultSchool = school
+		}
+
+		school.RebuildFreeRoomsIndex()
+	}
 }
@@ -0,0 +1,183 @@
+// handlers/health.go
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/metrics"
+	"smuggr.xyz/goptivum/core/scraper"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+	StatusUnknown = "unknown"
+)
+
+var startTime = time.Now()
+
+// ProviderStatus remembers the outcome of the calls to an upstream
+// provider, so the health checks don't have to call it themselves
+type ProviderStatus struct {
+	provider    string
+	mu          sync.RWMutex
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+}
+
+var (
+	OpenWeatherStatus         = NewProviderStatus("open_weather")
+	LocalWeatherStationStatus = NewProviderStatus("local_weather_station")
+)
+
+func NewProviderStatus(provider string) *ProviderStatus {
+	return &ProviderStatus{provider: provider}
+}
+
+// Records the outcome of a call to the provider
+func (p *ProviderStatus) Record(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	metrics.WeatherRequests.WithLabelValues(p.provider).Inc()
+	if err != nil {
+		metrics.WeatherFailures.WithLabelValues(p.provider).Inc()
+		p.lastFailure = time.Now()
+		p.lastError = err.Error()
+		return
+	}
+
+	p.lastSuccess = time.Now()
+}
+
+// The provider is failing if its last call failed, unknown if it hasn't been called yet
+func (p *ProviderStatus) Health() *models.ComponentHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	health := &models.ComponentHealth{
+		Status:      StatusUnknown,
+		LastSuccess: unixMilli(p.lastSuccess),
+		LastFailure: unixMilli(p.lastFailure),
+	}
+
+	switch {
+	case p.lastFailure.After(p.lastSuccess):
+		health.Status = StatusFailing
+		health.Error = p.lastError
+	case !p.lastSuccess.IsZero():
+		health.Status = StatusOK
+	}
+
+	return health
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+func datastoreHealth() *models.ComponentHealth {
+	if err := datastore.Ping(); err != nil {
+		return &models.ComponentHealth{
+			Status: StatusFailing,
+			Error:  err.Error(),
+		}
+	}
+
+	return &models.ComponentHealth{Status: StatusOK}
+}
+
+func resourceHealth(resource *scraper.ScraperResource) *models.ResourceHealth {
+	stats := resource.HubStats()
+
+	health := &models.ResourceHealth{
+		Type:             resource.Type.String(),
+		Indexes:          int64(len(resource.GetIndexes())),
+		Observers:        stats.Observers,
+		FailingObservers: stats.FailingObservers,
+		Checks:           stats.Checks,
+		FailedChecks:     stats.FailedChecks,
+		LastChecked:      unixMilli(stats.LastSuccess),
+		LastScraped:      unixMilli(resource.LastScraped()),
+	}
+
+	if stats.Checks > 0 {
+		health.FailureRate = float64(stats.FailedChecks) / float64(stats.Checks)
+	}
+
+	return health
+}
+
+func (s *School) health() *models.SchoolHealth {
+	health := &models.SchoolHealth{
+		Id:      s.ID,
+		Ready:   s.Ready(),
+		Serving: s.Serving(),
+		SseClients: map[string]int64{
+			"divisions": s.DivisionsHub.GetConnectedClients(),
+			"teachers":  s.TeachersHub.GetConnectedClients(),
+			"rooms":     s.RoomsHub.GetConnectedClients(),
+			"periods":   s.PeriodsHub.GetConnectedClients(),
+			"events":    s.EventsHub.GetConnectedClients(),
+		},
+	}
+
+	for _, resource := range []*scraper.ScraperResource{s.Divisions, s.Teachers, s.Rooms, s.Substitutions} {
+		health.Resources = append(health.Resources, resourceHealth(resource))
+	}
+
+	return health
+}
+
+// Always succeeds while the process can serve requests
+func LiveHandler(c *gin.Context) {
+	Respond(c, http.StatusOK, &models.LivenessResponse{
+		Live:   true,
+		Uptime: int64(time.Since(startTime).Seconds()),
+	})
+}
+
+// Reports the state of every component from what's been cached, without
+// calling upstream, it's ready once the datastore is open and every school
+// serves schedules, stored ones count so an unreachable site doesn't take
+// the instance out of rotation
+func ReadyHandler(c *gin.Context) {
+	readiness := &models.ReadinessResponse{
+		Datastore: datastoreHealth(),
+		Weather: map[string]*models.ComponentHealth{
+			OpenWeatherStatus.provider: OpenWeatherStatus.Health(),
+		},
+		SseClients: map[string]int64{
+			"clients": ClientsHub.GetConnectedClients(),
+		},
+	}
+
+	if Config.UseLocalWeatherStation {
+		readiness.Weather[LocalWeatherStationStatus.provider] = LocalWeatherStationStatus.Health()
+	}
+
+	readiness.Ready = readiness.Datastore.Status == StatusOK
+	for _, school := range Schools {
+		schoolHealth := school.health()
+		readiness.Schools = append(readiness.Schools, schoolHealth)
+		readiness.Ready = readiness.Ready && schoolHealth.Serving
+	}
+
+	code := http.StatusOK
+	if !readiness.Ready {
+		code = http.StatusServiceUnavailable
+	}
+
+	Respond(c, code, readiness)
+}
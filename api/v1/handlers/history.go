@@ -0,0 +1,209 @@
+// handlers/history.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/diff"
+	"smuggr.xyz/goptivum/core/scraper"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+type scheduledItem interface {
+	proto.Message
+	GetSchedule() *models.Schedule
+}
+
+func newScheduledItem(resourceType scraper.ResourceType) scheduledItem {
+	switch resourceType {
+	case scraper.DivisionResource:
+		return &models.Division{}
+	case scraper.TeacherResource:
+		return &models.Teacher{}
+	case scraper.RoomResource:
+		return &models.Room{}
+	}
+
+	return nil
+}
+
+func parseIndexParam(c *gin.Context) (int64, bool) {
+	index, err := strconv.ParseInt(c.Param("index"), 10, 64)
+	if err != nil {
+		Respond(c, http.StatusBadRequest, models.APIResponse{
+			Message: "invalid index",
+			Success: false,
+		})
+		return 0, false
+	}
+
+	return index, true
+}
+
+// Parses an optional version query parameter, 0 means it wasn't given
+func parseVersionQuery(c *gin.Context, key string) (int64, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		Respond(c, http.StatusBadRequest, models.APIResponse{
+			Message: fmt.Sprintf("invalid %s version", key),
+			Success: false,
+		})
+		return 0, false
+	}
+
+	return version, true
+}
+
+func getHistory(c *gin.Context, resourceType scraper.ResourceType, index int64) (*models.History, bool) {
+	history, err := getSchool(c).Store.GetHistory(resourceType.String(), index)
+	if err != nil {
+		Respond(c, http.StatusInternalServerError, models.APIResponse{
+			Message: err.Error(),
+			Success: false,
+		})
+		return nil, false
+	}
+
+	if len(history.Versions) == 0 {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: fmt.Sprintf("%s history not found", resourceType),
+			Success: false,
+		})
+		return nil, false
+	}
+
+	return history, true
+}
+
+func (s *School) getVersion(resourceType scraper.ResourceType, index int64, version int64) (scheduledItem, error) {
+	item := newScheduledItem(resourceType)
+	if err := s.Store.GetVersion(resourceType.String(), index, version, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func getScheduleVersion(c *gin.Context, resourceType scraper.ResourceType, index int64, version int64) (*models.Schedule, bool) {
+	item, err := getSchool(c).getVersion(resourceType, index, version)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: fmt.Sprintf("%s version %d not found", resourceType, version),
+			Success: false,
+		})
+		return nil, false
+	}
+
+	return item.GetSchedule(), true
+}
+
+func respondHistory(c *gin.Context, resourceType scraper.ResourceType) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	history, ok := getHistory(c, resourceType, index)
+	if !ok {
+		return
+	}
+
+	Respond(c, http.StatusOK, history)
+}
+
+// Diffs two versions of the schedule, "to" defaults to the latest version
+// and "from" to the one preceding "to" (an empty schedule if there's none)
+func respondDiff(c *gin.Context, resourceType scraper.ResourceType) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	from, ok := parseVersionQuery(c, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := parseVersionQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	history, ok := getHistory(c, resourceType, index)
+	if !ok {
+		return
+	}
+
+	if to == 0 {
+		to = history.Versions[len(history.Versions)-1]
+	}
+
+	if from == 0 {
+		position, found := slices.BinarySearch(history.Versions, to)
+		if found && position > 0 {
+			from = history.Versions[position-1]
+		}
+	}
+
+	var fromSchedule *models.Schedule
+	if from != 0 {
+		if fromSchedule, ok = getScheduleVersion(c, resourceType, index, from); !ok {
+			return
+		}
+	}
+
+	toSchedule, ok := getScheduleVersion(c, resourceType, index, to)
+	if !ok {
+		return
+	}
+
+	changes, err := diff.Schedules(fromSchedule, toSchedule)
+	if err != nil {
+		Respond(c, http.StatusConflict, models.APIResponse{
+			Message: err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	Respond(c, http.StatusOK, &models.ScheduleDiff{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	})
+}
+
+func GetDivisionHistoryHandler(c *gin.Context) {
+	respondHistory(c, scraper.DivisionResource)
+}
+
+func GetDivisionDiffHandler(c *gin.Context) {
+	respondDiff(c, scraper.DivisionResource)
+}
+
+func GetTeacherHistoryHandler(c *gin.Context) {
+	respondHistory(c, scraper.TeacherResource)
+}
+
+func GetTeacherDiffHandler(c *gin.Context) {
+	respondDiff(c, scraper.TeacherResource)
+}
+
+func GetRoomHistoryHandler(c *gin.Context) {
+	respondHistory(c, scraper.RoomResource)
+}
+
+func GetRoomDiffHandler(c *gin.Context) {
+	respondDiff(c, scraper.RoomResource)
+}
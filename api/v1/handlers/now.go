@@ -0,0 +1,96 @@
+// handlers/now.go
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/scraper"
+	"smuggr.xyz/goptivum/core/timetable"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Responds with the current and the next lesson group of the schedule,
+// today's substitutions are expected to be applied by the caller
+func (s *School) respondNow(c *gin.Context, schedule *models.Schedule, now time.Time) {
+	// The bell schedule is optional, lessons carry their own time ranges
+	periods, _ := s.Store.GetPeriods()
+
+	Respond(c, http.StatusOK, timetable.Now(schedule, periods, now))
+}
+
+// Returns the current time, replaced in tests
+var clock = time.Now
+
+func (s *School) now() (time.Time, string) {
+	now := clock().In(s.Location)
+	return now, now.Format(scraper.SubstitutionsDateLayout)
+}
+
+func GetDivisionNowHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	division, err := school.Store.GetDivision(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "division not found",
+			Success: false,
+		})
+		return
+	}
+
+	now, date := school.now()
+	school.overlayDivisionSubstitutions(division, date)
+
+	school.respondNow(c, division.Schedule, now)
+}
+
+func GetTeacherNowHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	teacher, err := school.Store.GetTeacher(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "teacher not found",
+			Success: false,
+		})
+		return
+	}
+
+	now, date := school.now()
+	school.overlayTeacherSubstitutions(teacher, date)
+
+	school.respondNow(c, teacher.Schedule, now)
+}
+
+func GetRoomNowHandler(c *gin.Context) {
+	index, ok := parseIndexParam(c)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	room, err := school.Store.GetRoom(index)
+	if err != nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "room not found",
+			Success: false,
+		})
+		return
+	}
+
+	now, date := school.now()
+	school.overlayRoomSubstitutions(room, date)
+
+	school.respondNow(c, room.Schedule, now)
+}
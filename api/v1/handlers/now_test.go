@@ -0,0 +1,121 @@
+// handlers/now_test.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Calls the handler as if the request was made at the given time
+func callNowHandler(t *testing.T, school *School, handler gin.HandlerFunc, index string, now time.Time) *httptest.ResponseRecorder {
+	t.Helper()
+
+	previousClock := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() {
+		clock = previousClock
+	})
+
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Params = gin.Params{{Key: "index", Value: index}}
+	c.Set(schoolKey, school)
+
+	handler(c)
+	return recorder
+}
+
+func TestGetDivisionNowHandler(t *testing.T) {
+	setupDatastore(t)
+	school := newTestSchool("test", "test:")
+	school.Location = time.UTC
+
+	// No bell schedule is stored, the lessons carry their own time ranges
+	division := divisionWithGroups()
+	for _, lesson := range division.Schedule.ScheduleDays[0].LessonGroups[0].Lessons {
+		lesson.TimeRange = &models.TimeRange{
+			Start: &models.Timestamp{Hour: 8, Minute: 0},
+			End:   &models.Timestamp{Hour: 8, Minute: 45},
+		}
+	}
+	if err := school.Store.SetDivision(division); err != nil {
+		t.Fatalf("error saving division: %v", err)
+	}
+
+	err := school.Store.SetSubstitutions(&models.Substitutions{
+		Date: substitutionsDate,
+		Substitutions: []*models.Substitution{{
+			Period:             1,
+			AbsentTeacher:      "J.Kowalski",
+			AbsentTeacherIndex: 1,
+			DivisionDesignator: "3TI",
+			DivisionIndex:      3,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("error saving substitutions: %v", err)
+	}
+
+	monday := time.Date(2024, time.October, 14, 8, 10, 0, 0, time.UTC)
+
+	t.Run("during a lesson", func(t *testing.T) {
+		recorder := callNowHandler(t, school, GetDivisionNowHandler, "3", monday)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status: got %d, want %d", recorder.Code, http.StatusOK)
+		}
+
+		var response models.NowResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error parsing response: %v", err)
+		}
+
+		if response.Current == nil || response.Current.Period != 1 {
+			t.Fatalf("current: got %v, want period 1", response.Current)
+		}
+		if response.Remaining != 35*60 {
+			t.Errorf("remaining: got %d, want %d", response.Remaining, 35*60)
+		}
+		if response.Current.Lessons[0].Substitution == nil {
+			t.Error("today's substitution wasn't applied")
+		}
+	})
+
+	t.Run("a week later", func(t *testing.T) {
+		recorder := callNowHandler(t, school, GetDivisionNowHandler, "3", monday.AddDate(0, 0, 7))
+
+		var response models.NowResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error parsing response: %v", err)
+		}
+
+		if response.Current == nil || response.Current.Lessons[0].Substitution != nil {
+			t.Errorf("current: got %v, want the lesson without a substitution", response.Current)
+		}
+	})
+
+	tests := []struct {
+		index string
+		code  int
+	}{
+		{"4", http.StatusNotFound},
+		{"abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			recorder := callNowHandler(t, school, GetDivisionNowHandler, tt.index, monday)
+			if recorder.Code != tt.code {
+				t.Errorf("status: got %d, want %d", recorder.Code, tt.code)
+			}
+		})
+	}
+}
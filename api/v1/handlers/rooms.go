@@ -0,0 +1,122 @@
+// handlers/rooms.go
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/timetable"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *School) RebuildFreeRoomsIndex() {
+	var rooms []*models.Room
+	for _, index := range s.Rooms.GetIndexes() {
+		room, err := s.Store.GetRoom(index)
+		if err != nil {
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+
+	s.FreeRoomsIndex.Rebuild(rooms)
+	slog.Debug("rebuilt free rooms index", "school", s.ID, "rooms", len(rooms))
+}
+
+// Updates the free rooms index with the stored schedule of a single room,
+// like the rebuild it leaves out rooms that aren't stored
+func (s *School) UpdateFreeRoom(index int64) {
+	room, err := s.Store.GetRoom(index)
+	if err != nil {
+		s.FreeRoomsIndex.Remove(index)
+		return
+	}
+
+	s.FreeRoomsIndex.Update(room)
+}
+
+func respondBadRequest(c *gin.Context, message string) {
+	Respond(c, http.StatusBadRequest, models.APIResponse{
+		Message: message,
+		Success: false,
+	})
+}
+
+// Finds rooms without a lesson on the given day (0 is monday, today by default)
+// and period, the period can also be given as a time (at=HH:MM, now by default)
+func GetFreeRoomsHandler(c *gin.Context) {
+	school := getSchool(c)
+	now := time.Now().In(school.Location)
+
+	day := timetable.DayIndex(now)
+	if value := c.Query("day"); value != "" {
+		parsedDay, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsedDay < 0 || parsedDay > 6 {
+			respondBadRequest(c, "invalid day, expected 0 (monday) - 6 (sunday)")
+			return
+		}
+		day = parsedDay
+	}
+
+	var period int64
+	if value := c.Query("period"); value != "" {
+		parsedPeriod, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsedPeriod < 0 {
+			respondBadRequest(c, "invalid period")
+			return
+		}
+		period = parsedPeriod
+	} else {
+		minutes := timetable.MinutesOf(now)
+		if value := c.Query("at"); value != "" {
+			parsedMinutes, err := timetable.ParseClock(value)
+			if err != nil {
+				respondBadRequest(c, err.Error())
+				return
+			}
+			minutes = parsedMinutes
+		}
+
+		periods, err := school.Store.GetPeriods()
+		if err != nil {
+			Respond(c, http.StatusNotFound, models.APIResponse{
+				Message: "periods not found",
+				Success: false,
+			})
+			return
+		}
+
+		currentPeriod := timetable.PeriodAt(periods, minutes)
+		if currentPeriod == nil {
+			Respond(c, http.StatusNotFound, models.APIResponse{
+				Message: "no period at the given time",
+				Success: false,
+			})
+			return
+		}
+		period = currentPeriod.Number
+	}
+
+	filter := timetable.RoomsFilter{
+		Prefix: c.Query("prefix"),
+	}
+	if value := c.Query("floor"); value != "" {
+		floor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			respondBadRequest(c, "invalid floor")
+			return
+		}
+		filter.Floor = floor
+		filter.HasFloor = true
+	}
+
+	Respond(c, http.StatusOK, &models.FreeRooms{
+		Day:    day,
+		Period: period,
+		Rooms:  school.FreeRoomsIndex.FreeRooms(day, period, filter),
+	})
+}
@@ -6,8 +6,6 @@ import (
 	"strconv"
 
 	"smuggr.xyz/goptivum/common/models"
-	"smuggr.xyz/goptivum/core/datastore"
-	"smuggr.xyz/goptivum/core/scraper"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,7 +21,13 @@ func GetDivisionHandler(c *gin.Context) {
 		return
 	}
 
-	division, err := datastore.GetDivision(index)
+	date, ok := parseDateQuery(c, false)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	division, err := school.Store.GetDivision(index)
 	if err != nil {
 		if division == nil {
 			Respond(c, http.StatusNotFound, models.APIResponse{
@@ -40,11 +44,15 @@ func GetDivisionHandler(c *gin.Context) {
 		return
 	}
 
+	if date != "" {
+		school.overlayDivisionSubstitutions(division, date)
+	}
+
 	Respond(c, http.StatusOK, division)
 }
 
 func GetDivisionsHandler(c *gin.Context) {
-	Respond(c, http.StatusOK, scraper.DivisionsScraperResource.Metadata)
+	Respond(c, http.StatusOK, getSchool(c).Divisions.Metadata)
 }
 
 func GetTeacherHandler(c *gin.Context) {
@@ -57,7 +65,13 @@ func GetTeacherHandler(c *gin.Context) {
 		return
 	}
 
-	teacher, err := datastore.GetTeacher(index)
+	date, ok := parseDateQuery(c, false)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	teacher, err := school.Store.GetTeacher(index)
 	if err != nil {
 		if teacher == nil {
 			Respond(c, http.StatusNotFound, models.APIResponse{
@@ -74,11 +88,15 @@ func GetTeacherHandler(c *gin.Context) {
 		return
 	}
 
+	if date != "" {
+		school.overlayTeacherSubstitutions(teacher, date)
+	}
+
 	Respond(c, http.StatusOK, teacher)
 }
 
 func GetTeachersHandler(c *gin.Context) {
-	Respond(c, http.StatusOK, scraper.TeachersScraperResource.Metadata)
+	Respond(c, http.StatusOK, getSchool(c).Teachers.Metadata)
 }
 
 func GetRoomHandler(c *gin.Context) {
@@ -91,7 +109,13 @@ func GetRoomHandler(c *gin.Context) {
 		return
 	}
 
-	room, err := datastore.GetRoom(index)
+	date, ok := parseDateQuery(c, false)
+	if !ok {
+		return
+	}
+
+	school := getSchool(c)
+	room, err := school.Store.GetRoom(index)
 	if err != nil {
 		if room == nil {
 			Respond(c, http.StatusNotFound, models.APIResponse{
@@ -108,9 +132,34 @@ func GetRoomHandler(c *gin.Context) {
 		return
 	}
 
+	if date != "" {
+		school.overlayRoomSubstitutions(room, date)
+	}
+
 	Respond(c, http.StatusOK, room)
 }
 
 func GetRoomsHandler(c *gin.Context) {
-	Respond(c, http.StatusOK, scraper.RoomsScraperResource.Metadata)
+	Respond(c, http.StatusOK, getSchool(c).Rooms.Metadata)
+}
+
+func GetPeriodsHandler(c *gin.Context) {
+	periods, err := getSchool(c).Store.GetPeriods()
+	if err != nil {
+		if periods == nil {
+			Respond(c, http.StatusNotFound, models.APIResponse{
+				Message: "periods not found",
+				Success: false,
+			})
+			return
+		}
+
+		Respond(c, http.StatusInternalServerError, models.APIResponse{
+			Message: err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	Respond(c, http.StatusOK, periods)
 }
@@ -0,0 +1,108 @@
+// handlers/schools.go
+package handlers
+
+import (
+	"net/http"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/scraper"
+	"smuggr.xyz/goptivum/core/search"
+	"smuggr.xyz/goptivum/core/sse"
+	"smuggr.xyz/goptivum/core/timetable"
+
+	"github.com/gin-gonic/gin"
+)
+
+const schoolKey = "school"
+
+// School is a scraped school along with the indexes built
+// from its data and the hubs its events are sent to
+type School struct {
+	*scraper.School
+	FreeRoomsIndex *timetable.RoomsIndex
+	SearchIndex    *search.Index
+
+	DivisionsHub *sse.Hub
+	TeachersHub  *sse.Hub
+	RoomsHub     *sse.Hub
+	PeriodsHub   *sse.Hub
+
+	// Every event of the school, clients subscribe to the topics they need
+	EventsHub *sse.Hub
+}
+
+var (
+	Schools       []*School
+	DefaultSchool *School
+)
+
+func newSchool(scraperSchool *scraper.School) *School {
+	school := &School{
+		School:         scraperSchool,
+		FreeRoomsIndex: timetable.NewRoomsIndex(),
+		DivisionsHub:   sse.NewHub(Config.MaxSSEClients, nil, nil),
+		TeachersHub:    sse.NewHub(Config.MaxSSEClients, nil, nil),
+		RoomsHub:       sse.NewHub(Config.MaxSSEClients, nil, nil),
+		PeriodsHub:     sse.NewHub(Config.MaxSSEClients, nil, nil),
+		EventsHub:      sse.NewHub(Config.MaxSSEClients, nil, nil),
+	}
+	school.SearchIndex = search.NewIndex(school.loadSearchDocuments)
+
+	school.DivisionsHub.SetLabels(school.ID, "divisions")
+	school.TeachersHub.SetLabels(school.ID, "teachers")
+	school.RoomsHub.SetLabels(school.ID, "rooms")
+	school.PeriodsHub.SetLabels(school.ID, "periods")
+	school.EventsHub.SetLabels(school.ID, "events")
+
+	return school
+}
+
+// Returns the school with the given ID, nil if there's none
+func GetSchool(id string) *School {
+	for _, school := range Schools {
+		if school.ID == id {
+			return school
+		}
+	}
+
+	return nil
+}
+
+// Picks the school from the ":school" path parameter,
+// routes without the parameter are served from the default school
+func ResolveSchool(c *gin.Context) {
+	school := DefaultSchool
+	if id := c.Param("school"); id != "" {
+		school = GetSchool(id)
+	}
+
+	if school == nil {
+		Respond(c, http.StatusNotFound, models.APIResponse{
+			Message: "school not found",
+			Success: false,
+		})
+		c.Abort()
+		return
+	}
+
+	c.Set(schoolKey, school)
+	c.Next()
+}
+
+func getSchool(c *gin.Context) *School {
+	return c.MustGet(schoolKey).(*School)
+}
+
+func GetSchoolsHandler(c *gin.Context) {
+	schools := &models.Schools{}
+	for _, school := range Schools {
+		schools.Schools = append(schools.Schools, &models.SchoolSummary{
+			Id:      school.ID,
+			Name:    school.Name,
+			Default: school == DefaultSchool,
+			Ready:   school.Ready(),
+		})
+	}
+
+	Respond(c, http.StatusOK, schools)
+}
@@ -0,0 +1,118 @@
+// handlers/search.go
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/scraper"
+	"smuggr.xyz/goptivum/core/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Marks the search index for a rebuild, called whenever scraped data changes
+func (s *School) MarkSearchIndexDirty() {
+	s.SearchIndex.MarkDirty()
+}
+
+func scheduleSubjects(schedule *models.Schedule) []string {
+	var subjects []string
+	if schedule == nil {
+		return subjects
+	}
+
+	seen := make(map[string]bool)
+	for _, scheduleDay := range schedule.ScheduleDays {
+		for _, lessonGroup := range scheduleDay.LessonGroups {
+			for _, lesson := range lessonGroup.Lessons {
+				subject := lesson.SubjectName
+				if subject == "" {
+					subject = lesson.FullName
+				}
+
+				if subject != "" && !seen[subject] {
+					seen[subject] = true
+					subjects = append(subjects, subject)
+				}
+			}
+		}
+	}
+
+	return subjects
+}
+
+func (s *School) loadSearchDocuments() []*search.Document {
+	var documents []*search.Document
+
+	for _, index := range s.Divisions.GetIndexes() {
+		if division, err := s.Store.GetDivision(index); err == nil {
+			documents = append(documents, &search.Document{
+				Type:       scraper.DivisionResource.String(),
+				Index:      division.Index,
+				Designator: division.Designator,
+				FullName:   division.FullName,
+				Subjects:   scheduleSubjects(division.Schedule),
+			})
+		}
+	}
+
+	for _, index := range s.Teachers.GetIndexes() {
+		if teacher, err := s.Store.GetTeacher(index); err == nil {
+			documents = append(documents, &search.Document{
+				Type:       scraper.TeacherResource.String(),
+				Index:      teacher.Index,
+				Designator: teacher.Designator,
+				FullName:   teacher.FullName,
+				Subjects:   scheduleSubjects(teacher.Schedule),
+			})
+		}
+	}
+
+	for _, index := range s.Rooms.GetIndexes() {
+		if room, err := s.Store.GetRoom(index); err == nil {
+			documents = append(documents, &search.Document{
+				Type:       scraper.RoomResource.String(),
+				Index:      room.Index,
+				Designator: room.Designator,
+				FullName:   room.FullName,
+				Subjects:   scheduleSubjects(room.Schedule),
+			})
+		}
+	}
+
+	slog.Debug("rebuilt search index", "school", s.ID, "documents", len(documents))
+
+	return documents
+}
+
+func GetSearchHandler(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		respondBadRequest(c, "missing query")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsedLimit, err := strconv.Atoi(value)
+		if err != nil || parsedLimit <= 0 || parsedLimit > maxSearchLimit {
+			respondBadRequest(c, fmt.Sprintf("invalid limit, expected 1 - %d", maxSearchLimit))
+			return
+		}
+		limit = parsedLimit
+	}
+
+	Respond(c, http.StatusOK, &models.SearchResults{
+		Query:   query,
+		Results: getSchool(c).SearchIndex.Search(query, limit),
+	})
+}
@@ -0,0 +1,208 @@
+// handlers/substitutions.go
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/scraper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parses the "date" query parameter, returns ok = false (and responds)
+// if the date is malformed
+func parseDateQuery(c *gin.Context, defaultToToday bool) (date string, ok bool) {
+	date = c.Query("date")
+	if date == "" {
+		if defaultToToday {
+			return time.Now().Format(scraper.SubstitutionsDateLayout), true
+		}
+		return "", true
+	}
+
+	if _, err := time.Parse(scraper.SubstitutionsDateLayout, date); err != nil {
+		Respond(c, http.StatusBadRequest, models.APIResponse{
+			Message: "invalid date, expected YYYY-MM-DD",
+			Success: false,
+		})
+		return "", false
+	}
+
+	return date, true
+}
+
+// Returns the schedule day the date falls on, nil on weekends
+func scheduleDayOfDate(schedule *models.Schedule, date string) *models.ScheduleDay {
+	parsedDate, err := time.Parse(scraper.SubstitutionsDateLayout, date)
+	if err != nil || schedule == nil {
+		return nil
+	}
+
+	// ScheduleDays start on monday
+	day := (int(parsedDate.Weekday()) + 6) % 7
+	if day >= len(schedule.ScheduleDays) {
+		return nil
+	}
+
+	return schedule.ScheduleDays[day]
+}
+
+// Returns the lesson group of the period, creating it in order if it doesn't exist
+func lessonGroupOfPeriod(day *models.ScheduleDay, period int64) *models.LessonGroup {
+	position := len(day.LessonGroups)
+	for i, lessonGroup := range day.LessonGroups {
+		if lessonGroup.Period == period {
+			return lessonGroup
+		}
+
+		if lessonGroup.Period > period {
+			position = i
+			break
+		}
+	}
+
+	newLessonGroup := &models.LessonGroup{Period: period}
+	day.LessonGroups = append(day.LessonGroups, nil)
+	copy(day.LessonGroups[position+1:], day.LessonGroups[position:])
+	day.LessonGroups[position] = newLessonGroup
+
+	return newLessonGroup
+}
+
+func substitutionLesson(substitution *models.Substitution) *models.Lesson {
+	return &models.Lesson{
+		FullName:           substitution.Subject,
+		SubjectName:        substitution.Subject,
+		TeacherDesignator:  substitution.ReplacementTeacher,
+		TeacherIndex:       substitution.ReplacementTeacherIndex,
+		RoomDesignator:     substitution.RoomDesignator,
+		RoomIndex:          substitution.RoomIndex,
+		DivisionDesignator: substitution.DivisionDesignator,
+		DivisionIndex:      substitution.DivisionIndex,
+		Substitution:       substitution,
+	}
+}
+
+// Attaches the substitution to the lessons of its period that match, if none
+// match and appendIfUnmatched is set, a lesson is created from the substitution
+func applySubstitution(day *models.ScheduleDay, substitution *models.Substitution, matches func(*models.Lesson) bool, appendIfUnmatched bool) {
+	matched := false
+	for _, lessonGroup := range day.LessonGroups {
+		if lessonGroup.Period != substitution.Period {
+			continue
+		}
+
+		for _, lesson := range lessonGroup.Lessons {
+			if matches(lesson) {
+				lesson.Substitution = substitution
+				matched = true
+			}
+		}
+	}
+
+	if !matched && appendIfUnmatched {
+		lessonGroup := lessonGroupOfPeriod(day, substitution.Period)
+		lessonGroup.Lessons = append(lessonGroup.Lessons, substitutionLesson(substitution))
+	}
+}
+
+// Matches the lessons of the absent teacher, by index when both are known and
+// otherwise by the designator or name, an unknown teacher matches nothing
+func (s *School) taughtByAbsentTeacher(substitution *models.Substitution) func(*models.Lesson) bool {
+	return func(lesson *models.Lesson) bool {
+		if lesson.TeacherIndex != 0 && substitution.AbsentTeacherIndex != 0 {
+			return lesson.TeacherIndex == substitution.AbsentTeacherIndex
+		}
+
+		if substitution.AbsentTeacher == "" {
+			return false
+		}
+
+		if lesson.TeacherDesignator == substitution.AbsentTeacher {
+			return true
+		}
+
+		return lesson.TeacherIndex != 0 && s.Teachers.GetFullNameFromIndex(lesson.TeacherIndex) == substitution.AbsentTeacher
+	}
+}
+
+func matchesResource(index, substitutionIndex int64, designator, substitutionDesignator string) bool {
+	if index != 0 && substitutionIndex != 0 {
+		return index == substitutionIndex
+	}
+
+	return designator != "" && designator == substitutionDesignator
+}
+
+func (s *School) overlaySubstitutions(schedule *models.Schedule, date string, apply func(*models.ScheduleDay, *models.Substitution)) {
+	day := scheduleDayOfDate(schedule, date)
+	if day == nil {
+		return
+	}
+
+	// No substitutions stored for that date
+	substitutions, err := s.Store.GetSubstitutions(date)
+	if err != nil {
+		return
+	}
+
+	for _, substitution := range substitutions.Substitutions {
+		apply(day, substitution)
+	}
+}
+
+func (s *School) overlayDivisionSubstitutions(division *models.Division, date string) {
+	s.overlaySubstitutions(division.Schedule, date, func(day *models.ScheduleDay, substitution *models.Substitution) {
+		if matchesResource(division.Index, substitution.DivisionIndex, division.Designator, substitution.DivisionDesignator) {
+			applySubstitution(day, substitution, s.taughtByAbsentTeacher(substitution), true)
+		}
+	})
+}
+
+func (s *School) overlayTeacherSubstitutions(teacher *models.Teacher, date string) {
+	s.overlaySubstitutions(teacher.Schedule, date, func(day *models.ScheduleDay, substitution *models.Substitution) {
+		if matchesResource(teacher.Index, substitution.AbsentTeacherIndex, teacher.FullName, substitution.AbsentTeacher) {
+			applySubstitution(day, substitution, func(*models.Lesson) bool { return true }, false)
+		}
+
+		if matchesResource(teacher.Index, substitution.ReplacementTeacherIndex, teacher.FullName, substitution.ReplacementTeacher) {
+			applySubstitution(day, substitution, func(*models.Lesson) bool { return false }, true)
+		}
+	})
+}
+
+func (s *School) overlayRoomSubstitutions(room *models.Room, date string) {
+	s.overlaySubstitutions(room.Schedule, date, func(day *models.ScheduleDay, substitution *models.Substitution) {
+		if matchesResource(room.Index, substitution.RoomIndex, room.Designator, substitution.RoomDesignator) {
+			applySubstitution(day, substitution, s.taughtByAbsentTeacher(substitution), true)
+		}
+	})
+}
+
+func GetSubstitutionsHandler(c *gin.Context) {
+	date, ok := parseDateQuery(c, true)
+	if !ok {
+		return
+	}
+
+	substitutions, err := getSchool(c).Store.GetSubstitutions(date)
+	if err != nil {
+		if substitutions == nil {
+			Respond(c, http.StatusNotFound, models.APIResponse{
+				Message: "substitutions not found",
+				Success: false,
+			})
+			return
+		}
+
+		Respond(c, http.StatusInternalServerError, models.APIResponse{
+			Message: err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	Respond(c, http.StatusOK, substitutions)
+}
@@ -0,0 +1,88 @@
+// handlers/substitutions_test.go
+package handlers
+
+import (
+	"testing"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+// A monday
+const substitutionsDate = "2024-10-14"
+
+func divisionWithGroups() *models.Division {
+	return &models.Division{
+		Index:      3,
+		Designator: "3TI",
+		Schedule: &models.Schedule{
+			ScheduleDays: []*models.ScheduleDay{{
+				LessonGroups: []*models.LessonGroup{{
+					Period: 1,
+					Lessons: []*models.Lesson{
+						{FullName: "inf-1/2", TeacherIndex: 1, TeacherDesignator: "JK"},
+						{FullName: "j.ang-2/2", TeacherIndex: 2, TeacherDesignator: "AN"},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func TestOverlayDivisionSubstitutions(t *testing.T) {
+	setupDatastore(t)
+	school := newTestSchool("test", "test:")
+	school.Teachers.UpdateMetadata("JK", "J.Kowalski", 1)
+	school.Teachers.UpdateMetadata("AN", "A.Nowak", 2)
+
+	tests := []struct {
+		name          string
+		absent        string
+		absentIndex   int64
+		substituted   []string
+		appendsLesson bool
+	}{
+		{"by index", "J.Kowalski", 1, []string{"inf-1/2"}, false},
+		{"by name", "A.Nowak", 0, []string{"j.ang-2/2"}, false},
+		{"by designator", "JK", 0, []string{"inf-1/2"}, false},
+		{"unknown teacher", "X.Nieznany", 0, nil, true},
+		{"no teacher", "", 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := school.Store.SetSubstitutions(&models.Substitutions{
+				Date: substitutionsDate,
+				Substitutions: []*models.Substitution{{
+					Period:             1,
+					AbsentTeacher:      tt.absent,
+					AbsentTeacherIndex: tt.absentIndex,
+					ReplacementTeacher: "P.Wiśniewski",
+					DivisionDesignator: "3TI",
+					DivisionIndex:      3,
+					Subject:            "informatyka",
+				}},
+			})
+			if err != nil {
+				t.Fatalf("error saving substitutions: %v", err)
+			}
+
+			division := divisionWithGroups()
+			school.overlayDivisionSubstitutions(division, substitutionsDate)
+
+			lessons := division.Schedule.ScheduleDays[0].LessonGroups[0].Lessons
+			var substituted []string
+			for _, lesson := range lessons[:2] {
+				if lesson.Substitution != nil {
+					substituted = append(substituted, lesson.FullName)
+				}
+			}
+
+			if len(substituted) != len(tt.substituted) || (len(substituted) > 0 && substituted[0] != tt.substituted[0]) {
+				t.Errorf("substituted lessons: got %v, want %v", substituted, tt.substituted)
+			}
+			if appended := len(lessons) > 2; appended != tt.appendsLesson {
+				t.Errorf("appended a substitution lesson: got %v, want %v", appended, tt.appendsLesson)
+			}
+		})
+	}
+}
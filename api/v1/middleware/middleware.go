@@ -1,7 +1,15 @@
 // middleware/middleware.go
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "smuggr.xyz/goptivum/core/metrics"
+
+    "github.com/gin-gonic/gin"
+)
 
 func NormalizeTrailingSlashMiddleware() gin.HandlerFunc {
     return func(c *gin.Context) {
@@ -11,4 +19,32 @@ func NormalizeTrailingSlashMiddleware() gin.HandlerFunc {
         }
         c.Next()
     }
-}
\ No newline at end of file
+}
+
+// SSE and WebSocket streams stay open for as long as their clients are
+// connected, so they're timed apart from the other requests
+func isStreamRoute(route string) bool {
+    return strings.Contains(route, "/events") || strings.HasSuffix(route, "/ws")
+}
+
+// Counts and times the requests per route, the route is the registered
+// pattern (e.g. /api/v1/division/:index) so the label stays bounded
+func MetricsMiddleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        route := c.FullPath()
+        if route == "" {
+            route = "unmatched"
+        }
+
+        method := c.Request.Method
+        metrics.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+        if isStreamRoute(route) {
+            metrics.ObserveSince(metrics.HTTPStreamDuration.WithLabelValues(route), start)
+            return
+        }
+        metrics.ObserveSince(metrics.HTTPRequestDuration.WithLabelValues(method, route), start)
+    }
+}
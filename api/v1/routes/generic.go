@@ -3,6 +3,7 @@ package routes
 
 import (
 	"fmt"
+	"log/slog"
 
 	"smuggr.xyz/goptivum/api/v1/handlers"
 	"smuggr.xyz/goptivum/common/models"
@@ -11,10 +12,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupGenericRoutes(router *gin.Engine, rootGroup *gin.RouterGroup, scheduleChannels *models.ScheduleChannels, otherChannels *models.OtherChannels) {
+func SetupGenericRoutes(router *gin.Engine, rootGroup *gin.RouterGroup, otherChannels *models.OtherChannels) {
 	healthGroup := rootGroup.Group("/health")
 	{
 		healthGroup.GET("/ping", handlers.PingHandler)
+		healthGroup.GET("/live", handlers.LiveHandler)
+		healthGroup.GET("/ready", handlers.ReadyHandler)
 		healthGroup.GET("", handlers.APIHealthHandler)
 		healthGroup.GET("/", handlers.APIHealthHandler)
 	}
@@ -27,20 +30,15 @@ func SetupGenericRoutes(router *gin.Engine, rootGroup *gin.RouterGroup, schedule
 		otherChannels.Clients <- 1
 	}
 
-	var ClientsHub = sse.NewHub(Config.MaxSSEClientsAnalytics, clientUnregisterCallback, clientRegisterCallback)
-	var DivisionsHub = sse.NewHub(Config.MaxSSEClients, nil, nil)
-	var TeachersHub = sse.NewHub(Config.MaxSSEClients, nil, nil)
-	var RoomsHub = sse.NewHub(Config.MaxSSEClients, nil, nil)
+	handlers.ClientsHub = sse.NewHub(Config.MaxSSEClientsAnalytics, clientUnregisterCallback, clientRegisterCallback)
+	handlers.ClientsHub.SetLabels("", "clients")
 
-	go ClientsHub.Run()
-	go DivisionsHub.Run()
-	go TeachersHub.Run()
-	go RoomsHub.Run()
+	go handlers.ClientsHub.Run()
 
 	analyticsGroup := rootGroup.Group("/analytics")
 	{
 		analyticsGroup.GET("/clients", func(c *gin.Context) {
-			clients := ClientsHub.GetConnectedClients()
+			clients := handlers.ClientsHub.GetConnectedClients()
 			if clients >= Config.MaxSSEClientsAnalytics {
 				handlers.Respond(c, 200, models.APIResponse{
 					Message: fmt.Sprintf(">%d", clients),
@@ -59,47 +57,14 @@ func SetupGenericRoutes(router *gin.Engine, rootGroup *gin.RouterGroup, schedule
 	sseGroup := rootGroup.Group("/events")
 	{
 		sseGroup.GET("/clients", func(c *gin.Context) {
-			ClientsHub.Handler()(c.Writer, c.Request)
-		})
-
-		sseGroup.GET("/divisions", func(c *gin.Context) {
-			DivisionsHub.Handler()(c.Writer, c.Request)
-		})
-
-		sseGroup.GET("/teachers", func(c *gin.Context) {
-			TeachersHub.Handler()(c.Writer, c.Request)
-		})
-
-		sseGroup.GET("/rooms", func(c *gin.Context) {
-			RoomsHub.Handler()(c.Writer, c.Request)
+			handlers.ClientsHub.Handler()(c.Writer, c.Request)
 		})
 	}
 
 	go func() {
 		for message := range otherChannels.Clients {
-			fmt.Println("broadcasting refresh for clients hub:", message)
-			ClientsHub.Broadcast(message)
-		}
-	}()
-
-	go func() {
-		for message := range scheduleChannels.Divisons {
-			fmt.Println("broadcasting refresh for divisions hub:", message)
-			DivisionsHub.Broadcast(message)
-		}
-	}()
-
-	go func() {
-		for message := range scheduleChannels.Teachers {
-			fmt.Println("broadcasting refresh for teachers hub:", message)
-			TeachersHub.Broadcast(message)
-		}
-	}()
-
-	go func() {
-		for message := range scheduleChannels.Rooms {
-			fmt.Println("broadcasting refresh for rooms hub:", message)
-			RoomsHub.Broadcast(message)
+			slog.Debug("broadcasting refresh", "hub", "clients", "clients", message)
+			handlers.ClientsHub.BroadcastEvent("clients.changed", message)
 		}
 	}()
 }
@@ -12,23 +12,26 @@ import (
 	//"github.com/didip/tollbooth_gin"
 	"github.com/gin-contrib/static"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var Config config.APIConfig
 
-func Initialize(defaultRouter *gin.Engine, scheduleChannels *models.ScheduleChannels, otherChannels *models.OtherChannels) {
+func Initialize(defaultRouter *gin.Engine, otherChannels *models.OtherChannels) {
 	Config = config.Global.API
 
 	//defaultLimiter := tollbooth.NewLimiter(0.5, nil)
 	defaultRouter.Use(static.Serve("/", static.LocalFile(os.Getenv("DIST_PATH"), false)))
 
+	defaultRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	rootGroup := defaultRouter.Group("/api/v1")
 	//rootGroup.Use(tollbooth_gin.LimitHandler(defaultLimiter))
 
 	handlers.Initialize()
 
-	SetupGenericRoutes(defaultRouter, rootGroup, scheduleChannels, otherChannels)
-	SetupScheduleRoutes(defaultRouter, rootGroup)
+	SetupGenericRoutes(defaultRouter, rootGroup, otherChannels)
+	SetupSchoolRoutes(defaultRouter, rootGroup)
 	SetupWeatherRoutes(defaultRouter, rootGroup)
 
 	defaultRouter.NoRoute(func(c *gin.Context) {
@@ -11,6 +11,10 @@ func SetupScheduleRoutes(router *gin.Engine, rootGroup *gin.RouterGroup) {
 	divisionGroup := rootGroup.Group("/division")
 	{
 		divisionGroup.GET("/:index", handlers.GetDivisionHandler)
+		divisionGroup.GET("/:index/history", handlers.GetDivisionHistoryHandler)
+		divisionGroup.GET("/:index/diff", handlers.GetDivisionDiffHandler)
+		divisionGroup.GET("/:index/calendar.ics", handlers.GetDivisionCalendarHandler)
+		divisionGroup.GET("/:index/now", handlers.GetDivisionNowHandler)
 	}
 	divisionsGroup := rootGroup.Group("/divisions")
 	{
@@ -21,6 +25,10 @@ func SetupScheduleRoutes(router *gin.Engine, rootGroup *gin.RouterGroup) {
 	teacherGroup := rootGroup.Group("/teacher")
 	{
 		teacherGroup.GET("/:index", handlers.GetTeacherHandler)
+		teacherGroup.GET("/:index/history", handlers.GetTeacherHistoryHandler)
+		teacherGroup.GET("/:index/diff", handlers.GetTeacherDiffHandler)
+		teacherGroup.GET("/:index/calendar.ics", handlers.GetTeacherCalendarHandler)
+		teacherGroup.GET("/:index/now", handlers.GetTeacherNowHandler)
 	}
 	teachersGroup := rootGroup.Group("/teachers")
 	{
@@ -31,10 +39,33 @@ func SetupScheduleRoutes(router *gin.Engine, rootGroup *gin.RouterGroup) {
 	roomGroup := rootGroup.Group("/room")
 	{
 		roomGroup.GET("/:index", handlers.GetRoomHandler)
+		roomGroup.GET("/:index/history", handlers.GetRoomHistoryHandler)
+		roomGroup.GET("/:index/diff", handlers.GetRoomDiffHandler)
+		roomGroup.GET("/:index/calendar.ics", handlers.GetRoomCalendarHandler)
+		roomGroup.GET("/:index/now", handlers.GetRoomNowHandler)
 	}
 	roomsGroup := rootGroup.Group("/rooms")
 	{
 		roomsGroup.GET("", handlers.GetRoomsHandler)
 		roomsGroup.GET("/", handlers.GetRoomsHandler)
+		roomsGroup.GET("/free", handlers.GetFreeRoomsHandler)
+	}
+
+	substitutionsGroup := rootGroup.Group("/substitutions")
+	{
+		substitutionsGroup.GET("", handlers.GetSubstitutionsHandler)
+		substitutionsGroup.GET("/", handlers.GetSubstitutionsHandler)
+	}
+
+	periodsGroup := rootGroup.Group("/periods")
+	{
+		periodsGroup.GET("", handlers.GetPeriodsHandler)
+		periodsGroup.GET("/", handlers.GetPeriodsHandler)
+	}
+
+	searchGroup := rootGroup.Group("/search")
+	{
+		searchGroup.GET("", handlers.GetSearchHandler)
+		searchGroup.GET("/", handlers.GetSearchHandler)
 	}
 }
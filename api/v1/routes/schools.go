@@ -0,0 +1,135 @@
+// routes/schools.go
+package routes
+
+import (
+	"log/slog"
+	"time"
+
+	"smuggr.xyz/goptivum/api/v1/handlers"
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/scraper"
+	"smuggr.xyz/goptivum/core/sse"
+	"smuggr.xyz/goptivum/core/timetable"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEventsRoutes(router *gin.Engine, rootGroup *gin.RouterGroup) {
+	sseGroup := rootGroup.Group("/events")
+	{
+		sseGroup.GET("/divisions", handlers.DivisionsEventsHandler)
+		sseGroup.GET("/teachers", handlers.TeachersEventsHandler)
+		sseGroup.GET("/rooms", handlers.RoomsEventsHandler)
+		sseGroup.GET("/periods", handlers.PeriodsEventsHandler)
+
+		sseGroup.GET("", handlers.EventsHandler)
+		sseGroup.GET("/", handlers.EventsHandler)
+		sseGroup.GET("/division/:index", handlers.DivisionEventsHandler)
+		sseGroup.GET("/teacher/:index", handlers.TeacherEventsHandler)
+		sseGroup.GET("/room/:index", handlers.RoomEventsHandler)
+	}
+
+	// Every events endpoint also accepts WebSocket upgrades, this one is
+	// for the clients that only look for a WebSocket endpoint
+	rootGroup.GET("/ws", handlers.EventsHandler)
+}
+
+// Sends the refresh as a typed event to the resource's hub and to the
+// subscribers of its topic, its data is the index of the refreshed item
+// (or its payloads) or the resource type when its list has changed
+func broadcastRefresh(school *handlers.School, hub *sse.Hub, event scraper.RefreshEvent) {
+	if event.Type == scraper.ListChangedRefresh {
+		hub.BroadcastEvent(event.Name(), event.Resource)
+		school.EventsHub.BroadcastTopic(event.Resource.String(), event.Name(), event.Resource)
+		return
+	}
+
+	sseEvent := sse.Event{Type: event.Name(), Data: event.Index}
+	if event.Type == scraper.UpdatedRefresh {
+		sseEvent.Payloads = school.RefreshPayloads(event.Resource, event.Index)
+	}
+
+	hub.Send(sseEvent)
+
+	sseEvent.Topic = handlers.ItemTopic(event.Resource, event.Index)
+	school.EventsHub.Send(sseEvent)
+}
+
+// Broadcasts the refreshes of the school's resources to its hubs
+func startSchoolHubs(school *handlers.School) {
+	go school.DivisionsHub.Run()
+	go school.TeachersHub.Run()
+	go school.RoomsHub.Run()
+	go school.PeriodsHub.Run()
+	go school.EventsHub.Run()
+
+	go func() {
+		for event := range school.Divisions.RefreshChan {
+			slog.Debug("broadcasting refresh", "school", school.ID, "hub", "divisions", "event", event.Name(), "index", event.Index)
+			broadcastRefresh(school, school.DivisionsHub, event)
+			school.MarkSearchIndexDirty()
+		}
+	}()
+
+	go func() {
+		for event := range school.Teachers.RefreshChan {
+			slog.Debug("broadcasting refresh", "school", school.ID, "hub", "teachers", "event", event.Name(), "index", event.Index)
+			broadcastRefresh(school, school.TeachersHub, event)
+			school.MarkSearchIndexDirty()
+		}
+	}()
+
+	go func() {
+		for event := range school.Rooms.RefreshChan {
+			slog.Debug("broadcasting refresh", "school", school.ID, "hub", "rooms", "event", event.Name(), "index", event.Index)
+			broadcastRefresh(school, school.RoomsHub, event)
+			// Added rooms are stored (and updated) one by one after the list changes
+			if event.Type != scraper.ListChangedRefresh {
+				school.UpdateFreeRoom(event.Index)
+			}
+			school.MarkSearchIndexDirty()
+		}
+	}()
+
+	// Clients refetch their "now" endpoints when a period starts or ends,
+	// the message is the number of the period that started (0 for a break),
+	// nothing is sent on days without lessons
+	ticker := time.NewTicker(time.Second)
+	go timetable.WatchPeriods(ticker.C, func() *models.Periods {
+		periods, _ := school.Store.GetPeriods()
+		return periods
+	}, school.FreeRoomsIndex.HasLessons, school.Location, func(period *models.Period) {
+		var number int64
+		eventType := "break.started"
+		if period != nil {
+			number = period.Number
+			eventType = "period.started"
+		}
+
+		slog.Debug("broadcasting period boundary", "school", school.ID, "hub", "periods", "period", number)
+		school.PeriodsHub.BroadcastEvent(eventType, number)
+		school.EventsHub.BroadcastTopic(handlers.PeriodsTopic, eventType, number)
+	}, nil)
+}
+
+// Every school is served under /schools/:school, the routes
+// without a school are aliases for the default school
+func SetupSchoolRoutes(router *gin.Engine, rootGroup *gin.RouterGroup) {
+	for _, school := range handlers.Schools {
+		startSchoolHubs(school)
+	}
+
+	schoolsGroup := rootGroup.Group("/schools")
+	{
+		schoolsGroup.GET("", handlers.GetSchoolsHandler)
+		schoolsGroup.GET("/", handlers.GetSchoolsHandler)
+	}
+
+	schoolGroup := schoolsGroup.Group("/:school", handlers.ResolveSchool)
+	SetupScheduleRoutes(router, schoolGroup)
+	SetupEventsRoutes(router, schoolGroup)
+
+	defaultSchoolGroup := rootGroup.Group("", handlers.ResolveSchool)
+	SetupScheduleRoutes(router, defaultSchoolGroup)
+	SetupEventsRoutes(router, defaultSchoolGroup)
+}
@@ -0,0 +1,118 @@
+// routes/schools_test.go
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"smuggr.xyz/goptivum/api/v1/handlers"
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/scraper"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/gin-gonic/gin"
+)
+
+// Serves two schools sharing an in-memory datastore, "zsem" keeps the
+// unprefixed keys of a single school deployment and is the default one
+func setupSchools(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("error opening datastore: %v", err)
+	}
+	// The school hubs keep reading it after the test, so it's never closed
+	datastore.DB = db
+
+	config.Global.API.MaxSSEClients = 10
+
+	zsem := scraper.NewSchool(config.SchoolConfig{ID: "zsem"})
+	lo1 := scraper.NewSchool(config.SchoolConfig{ID: "lo1", KeyPrefix: "lo1:"})
+	scraper.Schools = []*scraper.School{zsem, lo1}
+	scraper.DefaultSchool = zsem
+
+	divisions := map[*scraper.School][]*models.Division{
+		zsem: {{Index: 1, Designator: "1A"}},
+		lo1:  {{Index: 1, Designator: "1LO"}, {Index: 2, Designator: "2LO"}},
+	}
+	for school, schoolDivisions := range divisions {
+		for _, division := range schoolDivisions {
+			if err := school.Store.SetDivision(division); err != nil {
+				t.Fatalf("error saving division: %v", err)
+			}
+		}
+	}
+
+	handlers.Initialize()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupSchoolRoutes(router, router.Group("/api/v1"))
+
+	return router
+}
+
+func TestSchoolRoutes(t *testing.T) {
+	router := setupSchools(t)
+
+	tests := []struct {
+		path       string
+		code       int
+		designator string
+	}{
+		{"/api/v1/division/1", http.StatusOK, "1A"},
+		{"/api/v1/schools/zsem/division/1", http.StatusOK, "1A"},
+		{"/api/v1/schools/lo1/division/1", http.StatusOK, "1LO"},
+		{"/api/v1/schools/lo1/division/2", http.StatusOK, "2LO"},
+		{"/api/v1/division/2", http.StatusNotFound, ""},
+		{"/api/v1/schools/zsem/division/2", http.StatusNotFound, ""},
+		{"/api/v1/schools/unknown/division/1", http.StatusNotFound, ""},
+		{"/api/v1/schools/unknown/divisions", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest("GET", test.path, nil))
+
+			if recorder.Code != test.code {
+				t.Fatalf("got status %d, want %d: %s", recorder.Code, test.code, recorder.Body)
+			}
+			if test.designator == "" {
+				return
+			}
+
+			var division models.Division
+			if err := json.Unmarshal(recorder.Body.Bytes(), &division); err != nil {
+				t.Fatalf("error parsing division: %v", err)
+			}
+			if division.Designator != test.designator {
+				t.Errorf("got division %q, want %q", division.Designator, test.designator)
+			}
+		})
+	}
+}
+
+func TestSchoolStoresAreIsolated(t *testing.T) {
+	setupSchools(t)
+
+	expected := map[string]int{"zsem": 1, "lo1": 2}
+	for _, school := range handlers.Schools {
+		indexes, err := school.Store.GetIndexes("division")
+		if err != nil {
+			t.Fatalf("error reading %s indexes: %v", school.ID, err)
+		}
+		if len(indexes) != expected[school.ID] {
+			t.Errorf("school %s: got division indexes %v, want %d", school.ID, indexes, expected[school.ID])
+		}
+	}
+
+	if handlers.GetSchool("lo1").Store.Prefix != "lo1:" || handlers.DefaultSchool.ID != "zsem" {
+		t.Errorf("got default school %s, want zsem", handlers.DefaultSchool.ID)
+	}
+}
@@ -2,14 +2,16 @@
 package main
 
 import (
-	"fmt"
+	"flag"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata"
 
 	v1 "smuggr.xyz/goptivum/api/v1"
 	"smuggr.xyz/goptivum/common/config"
-	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/common/logger"
 	"smuggr.xyz/goptivum/common/utils"
 	"smuggr.xyz/goptivum/core/datastore"
 	"smuggr.xyz/goptivum/core/scraper"
@@ -19,25 +21,40 @@ func WaitForTermination() {
 	callChan := make(chan os.Signal, 1)
 	signal.Notify(callChan, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
-	fmt.Println("waiting for termination signal...")
+	slog.Info("waiting for termination signal")
 	<-callChan
-	fmt.Println("termination signal received")
+	slog.Info("termination signal received")
 }
 
 func Cleanup() {
-	fmt.Println("cleaning up...")
+	slog.Info("cleaning up")
 
 	scraper.Cleanup()
 	datastore.Cleanup()
 }
 
 func main() {
+	snapshotPath := flag.String("snapshot", "", "record the school's site into a directory or a .tar.gz archive and exit")
+	snapshotSchool := flag.String("school", "", "the school to record with -snapshot, the default school if empty")
+	flag.Parse()
+
 	if err := config.Initialize(); err != nil {
 		panic(err)
 	}
 
+	if err := logger.Initialize(&config.Global.Log); err != nil {
+		panic(err)
+	}
+
 	utils.Initialize()
 
+	if *snapshotPath != "" {
+		if err := scraper.Snapshot(*snapshotPath, *snapshotSchool); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	if err := datastore.Initialize(); err != nil {
 		panic(err)
 	}
@@ -47,11 +64,7 @@ func main() {
 		panic(err)
 	}
 
-	v1.Initialize(&models.ScheduleChannels{
-		Divisons: scraper.DivisionsScraperResource.RefreshChan,
-		Teachers: scraper.TeachersScraperResource.RefreshChan,
-		Rooms:    scraper.RoomsScraperResource.RefreshChan,
-	})
+	v1.Initialize()
 
 	defer Cleanup()
 
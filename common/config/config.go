@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -34,7 +35,7 @@ func loadConfig(config *GlobalConfig) error {
 }
 
 func Initialize() error {
-	fmt.Println("initializing config")
+	slog.Info("initializing config")
 
 	if err := loadEnv(); err != nil {
 		return err
@@ -56,7 +57,73 @@ func Initialize() error {
 		return err
 	}
 
-	fmt.Println("env and config loaded")
+	if err := Global.validateSchools(); err != nil {
+		return fmt.Errorf("invalid schools config: %w", err)
+	}
+
+	slog.Info("env and config loaded")
+
+	return nil
+}
+
+const DefaultSchoolID = "default"
+
+// Returns the configured schools, a config without a "schools" list
+// describes a single school with the top level "scraper" section, which
+// keeps its data under unprefixed keys
+func (c *GlobalConfig) SchoolConfigs() []SchoolConfig {
+	if len(c.Schools) == 0 {
+		return []SchoolConfig{{
+			ID:      DefaultSchoolID,
+			Scraper: c.Scraper,
+		}}
+	}
+
+	schools := make([]SchoolConfig, len(c.Schools))
+	for i, school := range c.Schools {
+		school.KeyPrefix = school.ID + ":"
+		if school.ConfiguredKeyPrefix != nil {
+			school.KeyPrefix = *school.ConfiguredKeyPrefix
+		}
+		schools[i] = school
+	}
+
+	return schools
+}
+
+// Returns the ID of the school the routes without a school are served from
+func (c *GlobalConfig) DefaultSchoolID() string {
+	if c.DefaultSchool != "" {
+		return c.DefaultSchool
+	}
+
+	schools := c.SchoolConfigs()
+	return schools[0].ID
+}
+
+// Schools sharing a key prefix would overwrite each other's data, so
+// prefixes are unique and only one school can keep unprefixed keys
+func (c *GlobalConfig) validateSchools() error {
+	seen := make(map[string]bool)
+	prefixes := make(map[string]string)
+	for _, school := range c.SchoolConfigs() {
+		if school.ID == "" {
+			return fmt.Errorf("school without an id")
+		}
+		if seen[school.ID] {
+			return fmt.Errorf("duplicate school id: %s", school.ID)
+		}
+		seen[school.ID] = true
+
+		if other, ok := prefixes[school.KeyPrefix]; ok {
+			return fmt.Errorf("schools %s and %s share the key prefix %q", other, school.ID, school.KeyPrefix)
+		}
+		prefixes[school.KeyPrefix] = school.ID
+	}
+
+	if !seen[c.DefaultSchoolID()] {
+		return fmt.Errorf("default school not found: %s", c.DefaultSchoolID())
+	}
 
 	return nil
 }
@@ -0,0 +1,74 @@
+// config/config_test.go
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func parseConfig(t *testing.T, data string) *GlobalConfig {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType("json")
+	if err := v.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatalf("error reading config: %v", err)
+	}
+
+	var config GlobalConfig
+	if err := v.Unmarshal(&config); err != nil {
+		t.Fatalf("error decoding config: %v", err)
+	}
+
+	return &config
+}
+
+func TestSchoolKeyPrefixes(t *testing.T) {
+	config := parseConfig(t, `{"schools": [
+		{"id": "zsem", "key_prefix": ""},
+		{"id": "lo1"},
+		{"id": "lo2", "key_prefix": "second:"}
+	]}`)
+
+	if err := config.validateSchools(); err != nil {
+		t.Fatalf("error validating schools: %v", err)
+	}
+
+	expected := map[string]string{"zsem": "", "lo1": "lo1:", "lo2": "second:"}
+	for _, school := range config.SchoolConfigs() {
+		if school.KeyPrefix != expected[school.ID] {
+			t.Errorf("school %s: got prefix %q, want %q", school.ID, school.KeyPrefix, expected[school.ID])
+		}
+	}
+}
+
+func TestValidateSchools(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		valid  bool
+	}{
+		{"single school", `{"scraper": {}}`, true},
+		{"default school", `{"schools": [{"id": "a"}, {"id": "b"}], "default_school": "b"}`, true},
+		{"unknown default school", `{"schools": [{"id": "a"}], "default_school": "b"}`, false},
+		{"missing id", `{"schools": [{"name": "a"}]}`, false},
+		{"duplicate id", `{"schools": [{"id": "a"}, {"id": "a"}]}`, false},
+		{"duplicate prefix", `{"schools": [{"id": "a", "key_prefix": "x:"}, {"id": "b", "key_prefix": "x:"}]}`, false},
+		{"prefix of another id", `{"schools": [{"id": "a"}, {"id": "b", "key_prefix": "a:"}]}`, false},
+		{"two empty prefixes", `{"schools": [{"id": "a", "key_prefix": ""}, {"id": "b", "key_prefix": ""}]}`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := parseConfig(t, test.config).validateSchools()
+			if test.valid && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("got no error, want one")
+			}
+		})
+	}
+}
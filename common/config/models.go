@@ -1,6 +1,8 @@
 // config/models.go
 package config
 
+import "time"
+
 type scraperEndpoints struct {
 	Division      string `mapstructure:"division"`
 	Teacher       string `mapstructure:"teacher"`
@@ -8,12 +10,14 @@ type scraperEndpoints struct {
 	DivisionsList string `mapstructure:"divisions_list"`
 	TeachersList  string `mapstructure:"teachers_list"`
 	RoomsList     string `mapstructure:"rooms_list"`
+	Substitutions string `mapstructure:"substitutions"`
 }
 
 type quantitiesWorkers struct {
-	Division int64 `mapstructure:"division"`
-	Teacher  int64 `mapstructure:"teacher"`
-	Room     int64 `mapstructure:"room"`
+	Division     int64 `mapstructure:"division"`
+	Teacher      int64 `mapstructure:"teacher"`
+	Room         int64 `mapstructure:"room"`
+	Substitution int64 `mapstructure:"substitution"`
 }
 
 type scraperQuantities struct {
@@ -26,12 +30,40 @@ type scraperStaticIndexes struct {
 	Rooms     []int64 `mapstructure:"rooms"`
 }
 
+type scraperTerm struct {
+	Start string `mapstructure:"start"`
+	End   string `mapstructure:"end"`
+}
+
+type pollingWindow struct {
+	Name     string        `mapstructure:"name"`
+	Days     []string      `mapstructure:"days"`
+	Dates    []string      `mapstructure:"dates"`
+	Start    string        `mapstructure:"start"`
+	End      string        `mapstructure:"end"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+type scraperPolling struct {
+	Interval      time.Duration   `mapstructure:"interval"`
+	MaxInterval   time.Duration   `mapstructure:"max_interval"`
+	BackoffFactor float64         `mapstructure:"backoff_factor"`
+	StableAfter   int64           `mapstructure:"stable_after"`
+	StableFactor  float64         `mapstructure:"stable_factor"`
+	Jitter        float64         `mapstructure:"jitter"`
+	Windows       []pollingWindow `mapstructure:"windows"`
+}
+
 type ScraperConfig struct {
 	BaseUrl            string               `mapstructure:"base_url"`
 	Endpoints          scraperEndpoints     `mapstructure:"endpoints"`
 	Quantities         scraperQuantities    `mapstructure:"quantities"`
 	StaticIndexes      scraperStaticIndexes `mapstructure:"static_indexes"`
 	IgnoreCertificates bool                 `mapstructure:"ignore_certificates"`
+	Timezone           string               `mapstructure:"timezone"`
+	Term               scraperTerm          `mapstructure:"term"`
+	Polling            scraperPolling       `mapstructure:"polling"`
+	IndexLayout        string               `mapstructure:"index_layout"`
 }
 
 type openWeatherEndpoints struct {
@@ -65,7 +97,25 @@ type APIConfig struct {
 	MaxSSEClientsAnalytics int64               `mapstructure:"max_sse_clients_analytics"`
 }
 
-type GlobalConfig struct {
+type LogConfig struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+type SchoolConfig struct {
+	ID      string        `mapstructure:"id"`
+	Name    string        `mapstructure:"name"`
 	Scraper ScraperConfig `mapstructure:"scraper"`
-	API     APIConfig     `mapstructure:"api"`
+	// Nil when the "key_prefix" isn't set, an empty one keeps unprefixed keys
+	ConfiguredKeyPrefix *string `mapstructure:"key_prefix"`
+	// Resolved by SchoolConfigs
+	KeyPrefix string `mapstructure:"-"`
+}
+
+type GlobalConfig struct {
+	Scraper       ScraperConfig  `mapstructure:"scraper"`
+	Schools       []SchoolConfig `mapstructure:"schools"`
+	DefaultSchool string         `mapstructure:"default_school"`
+	API           APIConfig      `mapstructure:"api"`
+	Log           LogConfig      `mapstructure:"log"`
 }
\ No newline at end of file
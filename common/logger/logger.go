@@ -0,0 +1,72 @@
+// logger/logger.go
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"smuggr.xyz/goptivum/common/config"
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// Replaces the default slog logger with one configured by the "log" section,
+// LOG_LEVEL and LOG_FORMAT override it
+func Initialize(logConfig *config.LogConfig) error {
+	levelName := logConfig.Level
+	if env := os.Getenv("LOG_LEVEL"); env != "" {
+		levelName = env
+	}
+
+	format := logConfig.Format
+	if env := os.Getenv("LOG_FORMAT"); env != "" {
+		format = env
+	}
+
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newHandler(format, level)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	slog.Info("logger initialized", "level", level, "format", format)
+
+	return nil
+}
+
+// Parses debug, info, warn or error, an empty level is info
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("invalid log level: %s", name)
+	}
+
+	return level, nil
+}
+
+func newHandler(format string, level slog.Level) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(format) {
+	case "", TextFormat:
+		return slog.NewTextHandler(os.Stdout, opts), nil
+	case JSONFormat:
+		return slog.NewJSONHandler(os.Stdout, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s", format)
+	}
+}
@@ -4,9 +4,3 @@ package models
 type OtherChannels struct {
 	Clients chan int64;
 }
-
-type ScheduleChannels struct {
-	Divisons chan int64;
-	Teachers chan int64;
-	Rooms    chan int64;
-}
\ No newline at end of file
@@ -2,7 +2,10 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"crypto/tls"
 	"strings"
@@ -10,41 +13,60 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"smuggr.xyz/goptivum/common/config"
 )
 
-var ScraperConfig config.ScraperConfig
 var HttpClient *http.Client
 
 func Initialize() {
-	fmt.Println("initializing utils")
-	ScraperConfig = config.Global.Scraper
-	
-	HttpClient = &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:        2000,
-			MaxIdleConnsPerHost: 1000,
-			IdleConnTimeout:     90 * time.Second,
-			TLSClientConfig:    &tls.Config{
-				// #nosec G402
-				InsecureSkipVerify: ScraperConfig.IgnoreCertificates,
-			},
+	slog.Info("initializing utils")
+	HttpClient = NewHttpClient(false)
+}
+
+func NewHttpClient(ignoreCertificates bool) *http.Client {
+	return &http.Client{
+		Transport: NewHttpTransport(ignoreCertificates),
+		Timeout:   20 * time.Second,
+	}
+}
+
+func NewHttpTransport(ignoreCertificates bool) *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:        2000,
+		MaxIdleConnsPerHost: 1000,
+		IdleConnTimeout:     90 * time.Second,
+		TLSClientConfig:    &tls.Config{
+			// #nosec G402
+			InsecureSkipVerify: ignoreCertificates,
 		},
-		Timeout: 20 * time.Second,
 	}
 }
 
-func CheckURL(url string) bool {
-	fmt.Println("checking URL:", url)
+// Returns the timezone, falls back to the local one if it's empty or unknown
+func LoadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		slog.Warn("error loading timezone, using local", "timezone", timezone, "error", err)
+		return time.Local
+	}
+
+	return location
+}
+
+func CheckURL(client *http.Client, url string) bool {
+	slog.Debug("checking URL", "url", url)
 
 	// #nosec G107
-	resp, err := HttpClient.Get(url)
+	resp, err := client.Get(url)
 	if err != nil {
 		return false
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("got status code: %d for %s\n", resp.StatusCode, url)
+	slog.Debug("got status code", "url", url, "status", resp.StatusCode)
 
 	return resp.StatusCode == http.StatusOK
 }
@@ -56,16 +78,17 @@ func IsEmptyOrInvisible(text string) bool {
 	return text == ""
 }
 
-func OpenDoc(baseUrl, endpoint string) (*goquery.Document, error) {
+// Fetches the page, retrying up to 3 times
+func Fetch(client *http.Client, baseUrl, endpoint string) ([]byte, error) {
 	url := fmt.Sprintf("%s%s", baseUrl, endpoint)
-	fmt.Printf("fetching URL: %s\n", url)
+	slog.Debug("fetching URL", "url", url)
 
 	var res *http.Response
 	var err error
 
 	for i := 0; i < 3; i++ {
 		// #nosec G107
-		res, err = HttpClient.Get(url)
+		res, err = client.Get(url)
 		if err == nil && res.StatusCode == http.StatusOK {
 			break
 		}
@@ -87,7 +110,21 @@ func OpenDoc(baseUrl, endpoint string) (*goquery.Document, error) {
 		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return body, nil
+}
+
+func OpenDoc(client *http.Client, baseUrl, endpoint string) (*goquery.Document, error) {
+	body, err := Fetch(client, baseUrl, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("error loading HTML: %w", err)
 	}
@@ -0,0 +1,293 @@
+// calendar/calendar.go
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	localTimeLayout = "20060102T150405"
+	utcTimeLayout   = "20060102T150405Z"
+	maxLineLength   = 75
+)
+
+type Term struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Parses the term dates (YYYY-MM-DD), missing dates default to
+// the school year the given time falls into (1st of September - 30th of June)
+func NewTerm(start, end string, now time.Time, location *time.Location) (Term, error) {
+	year := now.Year()
+	if now.Month() < time.September {
+		year--
+	}
+
+	term := Term{
+		Start: time.Date(year, time.September, 1, 0, 0, 0, 0, location),
+		End:   time.Date(year+1, time.June, 30, 0, 0, 0, 0, location),
+	}
+
+	if start != "" {
+		parsedStart, err := time.ParseInLocation(dateLayout, start, location)
+		if err != nil {
+			return term, fmt.Errorf("invalid term start: %w", err)
+		}
+		term.Start = parsedStart
+	}
+
+	if end != "" {
+		parsedEnd, err := time.ParseInLocation(dateLayout, end, location)
+		if err != nil {
+			return term, fmt.Errorf("invalid term end: %w", err)
+		}
+		term.End = parsedEnd
+	}
+
+	if term.End.Before(term.Start) {
+		return term, fmt.Errorf("term ends (%s) before it starts (%s)", term.End.Format(dateLayout), term.Start.Format(dateLayout))
+	}
+
+	return term, nil
+}
+
+type Calendar struct {
+	Name     string
+	UID      string
+	Schedule *models.Schedule
+	Term     Term
+	Location *time.Location
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\n", "\\n",
+	).Replace(s)
+}
+
+// Folds content lines longer than 75 octets, as required by RFC 5545,
+// the leading space of the continuation lines counts towards their length
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		// Don't split multi-byte characters
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// Only named locations can be referenced by a TZID, times in any other
+// (time.Local when no timezone is configured) are written in UTC
+func hasTZID(location *time.Location) bool {
+	return location != time.Local && location.String() != "Local"
+}
+
+func (c *Calendar) writeTime(b *strings.Builder, property string, t time.Time) {
+	if hasTZID(c.Location) {
+		writeLine(b, fmt.Sprintf("%s;TZID=%s:%s", property, c.Location, t.Format(localTimeLayout)))
+		return
+	}
+
+	writeLine(b, property+":"+t.UTC().Format(utcTimeLayout))
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// Finds the exact moment the UTC offset changes between from and to
+func findTransition(location *time.Location, from, to time.Time) time.Time {
+	_, fromOffset := from.In(location).Zone()
+	for to.Sub(from) > time.Second {
+		middle := from.Add(to.Sub(from) / 2)
+		if _, offset := middle.In(location).Zone(); offset == fromOffset {
+			from = middle
+		} else {
+			to = middle
+		}
+	}
+	return to
+}
+
+// Writes a VTIMEZONE with the offset transitions happening during the term
+func writeTimezone(b *strings.Builder, location *time.Location, term Term) {
+	writeLine(b, "BEGIN:VTIMEZONE")
+	writeLine(b, "TZID:"+location.String())
+
+	start := term.Start.AddDate(0, 0, -1)
+	end := term.End.AddDate(0, 0, 1)
+
+	writeObservance := func(at time.Time, offsetFrom int) {
+		name, offset := at.In(location).Zone()
+		component := "STANDARD"
+		if at.In(location).IsDST() {
+			component = "DAYLIGHT"
+		}
+
+		writeLine(b, "BEGIN:"+component)
+		// The onset is expressed in the local time in effect before the transition
+		writeLine(b, "DTSTART:"+at.In(time.FixedZone("", offsetFrom)).Format(localTimeLayout))
+		writeLine(b, "TZOFFSETFROM:"+formatOffset(offsetFrom))
+		writeLine(b, "TZOFFSETTO:"+formatOffset(offset))
+		writeLine(b, "TZNAME:"+escapeText(name))
+		writeLine(b, "END:"+component)
+	}
+
+	_, startOffset := start.In(location).Zone()
+	writeObservance(start, startOffset)
+
+	previous := start
+	for day := start.Add(24 * time.Hour); !day.After(end); day = day.Add(24 * time.Hour) {
+		_, previousOffset := previous.In(location).Zone()
+		if _, offset := day.In(location).Zone(); offset != previousOffset {
+			writeObservance(findTransition(location, previous, day), previousOffset)
+		}
+		previous = day
+	}
+
+	writeLine(b, "END:VTIMEZONE")
+}
+
+// Returns the first date on or after the term start that falls on the day
+// of the schedule (0 is monday)
+func firstOccurrence(term Term, day int) time.Time {
+	weekday := time.Weekday((day + 1) % 7)
+	date := term.Start
+	for date.Weekday() != weekday {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+func atTimestamp(date time.Time, timestamp *models.Timestamp) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), int(timestamp.Hour), int(timestamp.Minute), 0, 0, date.Location())
+}
+
+func describeLesson(lesson *models.Lesson) string {
+	var parts []string
+	if lesson.TeacherDesignator != "" {
+		parts = append(parts, lesson.TeacherDesignator)
+	}
+	if lesson.DivisionDesignator != "" {
+		division := lesson.DivisionDesignator
+		// On teacher and room pages the group follows the division, not the lesson name
+		if lesson.GroupLabel != "" && lesson.SubjectName == lesson.FullName {
+			division += "-" + lesson.GroupLabel
+		}
+		parts = append(parts, division)
+	}
+	if lesson.RoomDesignator != "" {
+		parts = append(parts, lesson.RoomDesignator)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Keeps the letters and digits of the text, anything else becomes a dash
+func uidPart(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+}
+
+// The lesson's key within its period stays the same when its teacher or room
+// changes, or when the other lessons move, so calendars update the event in place
+func lessonKey(lesson *models.Lesson) string {
+	var parts []string
+	for _, part := range []string{lesson.FullName, lesson.DivisionDesignator, lesson.GroupLabel} {
+		if part := uidPart(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Build renders the schedule as weekly recurring events lasting the whole term
+func (c *Calendar) Build(now time.Time) string {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//Goptivum//Schedule//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	if hasTZID(c.Location) {
+		writeLine(&b, "X-WR-TIMEZONE:"+c.Location.String())
+	}
+	writeLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	if hasTZID(c.Location) {
+		writeTimezone(&b, c.Location, c.Term)
+	}
+
+	until := time.Date(c.Term.End.Year(), c.Term.End.Month(), c.Term.End.Day(), 23, 59, 59, 0, c.Location).UTC()
+	stamp := now.UTC().Format(utcTimeLayout)
+
+	if c.Schedule != nil {
+		for day, scheduleDay := range c.Schedule.ScheduleDays {
+			date := firstOccurrence(c.Term, day)
+			if date.After(c.Term.End) {
+				continue
+			}
+
+			for _, lessonGroup := range scheduleDay.LessonGroups {
+				keys := make(map[string]int)
+
+				for _, lesson := range lessonGroup.Lessons {
+					if lesson.TimeRange == nil || lesson.TimeRange.Start == nil || lesson.TimeRange.End == nil {
+						continue
+					}
+
+					key := lessonKey(lesson)
+					// The same lesson twice in a period, e.g. both groups of a division in one room
+					if keys[key]++; keys[key] > 1 {
+						key = fmt.Sprintf("%s-%d", key, keys[key])
+					}
+
+					writeLine(&b, "BEGIN:VEVENT")
+					writeLine(&b, fmt.Sprintf("UID:%s-%d-%d-%s@goptivum", c.UID, day, lessonGroup.Period, key))
+					writeLine(&b, "DTSTAMP:"+stamp)
+					c.writeTime(&b, "DTSTART", atTimestamp(date, lesson.TimeRange.Start))
+					c.writeTime(&b, "DTEND", atTimestamp(date, lesson.TimeRange.End))
+					writeLine(&b, "RRULE:FREQ=WEEKLY;UNTIL="+until.Format(utcTimeLayout))
+					writeLine(&b, "SUMMARY:"+escapeText(lesson.FullName))
+					if lesson.RoomDesignator != "" {
+						writeLine(&b, "LOCATION:"+escapeText(lesson.RoomDesignator))
+					}
+					if description := describeLesson(lesson); description != "" {
+						writeLine(&b, "DESCRIPTION:"+escapeText(description))
+					}
+					writeLine(&b, "END:VEVENT")
+				}
+			}
+		}
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.String()
+}
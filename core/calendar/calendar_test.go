@@ -0,0 +1,204 @@
+// calendar/calendar_test.go
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+func TestWriteLineFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:matematyka"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multi-byte", "SUMMARY:" + strings.Repeat("zażółć gęślą jaźń ", 12)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			writeLine(&b, test.line)
+
+			output := b.String()
+			if !strings.HasSuffix(output, "\r\n") {
+				t.Fatalf("line isn't terminated with CRLF: %q", output)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > maxLineLength {
+					t.Errorf("line %d is %d octets long, want at most %d", i, len(line), maxLineLength)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i, line)
+				}
+			}
+
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(output, "\r\n"), "\r\n ", ""); unfolded != test.line {
+				t.Errorf("unfolded line: got %q, want %q", unfolded, test.line)
+			}
+		})
+	}
+}
+
+func mondayLesson() *models.Schedule {
+	return &models.Schedule{
+		ScheduleDays: []*models.ScheduleDay{{
+			LessonGroups: []*models.LessonGroup{{
+				Period: 1,
+				Lessons: []*models.Lesson{{
+					FullName: "matematyka",
+					TimeRange: &models.TimeRange{
+						Start: &models.Timestamp{Hour: 8, Minute: 0},
+						End:   &models.Timestamp{Hour: 8, Minute: 45},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+func buildLines(t *testing.T, location *time.Location) []string {
+	t.Helper()
+
+	now := time.Date(2024, time.October, 1, 12, 0, 0, 0, location)
+	term, err := NewTerm("2024-09-01", "2025-06-27", now, location)
+	if err != nil {
+		t.Fatalf("error parsing term: %v", err)
+	}
+
+	calendar := &Calendar{
+		Name:     "1A",
+		UID:      "division-1",
+		Schedule: mondayLesson(),
+		Term:     term,
+		Location: location,
+	}
+
+	return strings.Split(calendar.Build(now), "\r\n")
+}
+
+func contains(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuildTimezone(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	lines := buildLines(t, location)
+
+	// Both transitions of the term, each onset in the local time in effect before it
+	expected := []string{
+		"TZID:Europe/Warsaw",
+		"BEGIN:STANDARD",
+		"DTSTART:20241027T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20250330T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"DTSTART;TZID=Europe/Warsaw:20240902T080000",
+		"DTEND;TZID=Europe/Warsaw:20240902T084500",
+	}
+	for _, want := range expected {
+		if !contains(lines, want) {
+			t.Errorf("missing %q in:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestBuildWithoutTimezone(t *testing.T) {
+	lines := buildLines(t, time.Local)
+
+	for _, line := range lines {
+		if strings.Contains(line, "TZID") || strings.Contains(line, "VTIMEZONE") {
+			t.Errorf("got %q, want no timezone reference without a named timezone", line)
+		}
+	}
+
+	start := time.Date(2024, time.September, 2, 8, 0, 0, 0, time.Local).UTC().Format(utcTimeLayout)
+	if !contains(lines, "DTSTART:"+start) {
+		t.Errorf("missing the UTC start %s in:\n%s", start, strings.Join(lines, "\n"))
+	}
+}
+
+func uids(t *testing.T, schedule *models.Schedule) []string {
+	t.Helper()
+
+	now := time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+	term, err := NewTerm("2024-09-01", "2025-06-27", now, time.UTC)
+	if err != nil {
+		t.Fatalf("error parsing term: %v", err)
+	}
+
+	calendar := &Calendar{Name: "2TI", UID: "division-2", Schedule: schedule, Term: term, Location: time.UTC}
+
+	var uids []string
+	for _, line := range strings.Split(calendar.Build(now), "\r\n") {
+		if uid, ok := strings.CutPrefix(line, "UID:"); ok {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+func TestEventUIDs(t *testing.T) {
+	timeRange := &models.TimeRange{
+		Start: &models.Timestamp{Hour: 7, Minute: 10},
+		End:   &models.Timestamp{Hour: 7, Minute: 55},
+	}
+	lesson := func(fullName, group, teacher string) *models.Lesson {
+		return &models.Lesson{FullName: fullName, GroupLabel: group, TeacherDesignator: teacher, TimeRange: timeRange}
+	}
+	schedule := func(groups ...*models.LessonGroup) *models.Schedule {
+		return &models.Schedule{ScheduleDays: []*models.ScheduleDay{{LessonGroups: groups}}}
+	}
+
+	before := uids(t, schedule(
+		&models.LessonGroup{Period: 0, Lessons: []*models.Lesson{lesson("religia", "", "KS")}},
+		&models.LessonGroup{Period: 1, Lessons: []*models.Lesson{lesson("inf-1/2", "1/2", "AN"), lesson("j.ang-2/2", "2/2", "JK")}},
+	))
+	want := []string{
+		"division-2-0-0-religia@goptivum",
+		"division-2-0-1-inf-1-2-1-2@goptivum",
+		"division-2-0-1-j-ang-2-2-2-2@goptivum",
+	}
+	if strings.Join(before, " ") != strings.Join(want, " ") {
+		t.Fatalf("got UIDs %v, want %v", before, want)
+	}
+
+	// Lesson 0 is dropped, the first group of the period gets a new teacher
+	after := uids(t, schedule(
+		&models.LessonGroup{Period: 1, Lessons: []*models.Lesson{lesson("j.ang-2/2", "2/2", "JK"), lesson("inf-1/2", "1/2", "PW")}},
+	))
+	if len(after) != 2 || !contains(before, after[0]) || !contains(before, after[1]) {
+		t.Errorf("got UIDs %v, want the ones of period 1 kept from %v", after, before)
+	}
+
+	// The same lesson twice in a period still gets distinct UIDs
+	twice := uids(t, schedule(
+		&models.LessonGroup{Period: 2, Lessons: []*models.Lesson{lesson("wf", "", "AN"), lesson("wf", "", "JK")}},
+	))
+	if len(twice) != 2 || twice[0] == twice[1] {
+		t.Errorf("got UIDs %v, want two distinct ones", twice)
+	}
+}
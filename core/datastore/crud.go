@@ -2,7 +2,10 @@
 package datastore
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
+	"strconv"
 
 	"smuggr.xyz/goptivum/common/models"
 
@@ -16,13 +19,13 @@ func setItem(key []byte, item proto.Message) error {
 		return err
 	}
 
-	return DB.Update(func(txn *badger.Txn) error {
+	return update(func(txn *badger.Txn) error {
 		return txn.Set(key, data)
 	})
 }
 
 func getItem(key []byte, item proto.Message) error {
-	return DB.View(func(txn *badger.Txn) error {
+	return view(func(txn *badger.Txn) error {
 		entry, err := txn.Get(key)
 		if err != nil {
 			return err
@@ -37,8 +40,35 @@ func getItem(key []byte, item proto.Message) error {
 	})
 }
 
+// Stores the item and its new version in a single transaction
+func (s *Store) setItemWithHistory(key []byte, resourceType string, index int64, item proto.Message) error {
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	return update(func(txn *badger.Txn) error {
+		if err := txn.Set(key, data); err != nil {
+			return err
+		}
+
+		return s.appendHistory(txn, resourceType, index, item, data)
+	})
+}
+
+// Deletes the item along with its history
+func (s *Store) deleteItemWithHistory(key []byte, resourceType string, index int64) error {
+	return update(func(txn *badger.Txn) error {
+		if err := txn.Delete(key); err != nil && err != badger.ErrKeyNotFound {
+			return err
+		}
+
+		return s.deleteHistory(txn, resourceType, index)
+	})
+}
+
 func deleteItem(key []byte) error {
-	return DB.Update(func(txn *badger.Txn) error {
+	return update(func(txn *badger.Txn) error {
 		err := txn.Delete(key)
 		if err != nil && err != badger.ErrKeyNotFound {
 			return err
@@ -47,13 +77,13 @@ func deleteItem(key []byte) error {
 	})
 }
 
-func SetDivision(division *models.Division) error {
-	key := []byte(fmt.Sprintf("division:%d", division.Index))
-	return setItem(key, division)
+func (s *Store) SetDivision(division *models.Division) error {
+	key := s.key(fmt.Sprintf("division:%d", division.Index))
+	return s.setItemWithHistory(key, "division", division.Index, division)
 }
 
-func GetDivision(index int64) (*models.Division, error) {
-	key := []byte(fmt.Sprintf("division:%d", index))
+func (s *Store) GetDivision(index int64) (*models.Division, error) {
+	key := s.key(fmt.Sprintf("division:%d", index))
 	division := &models.Division{}
 	err := getItem(key, division)
 	if err != nil {
@@ -62,18 +92,18 @@ func GetDivision(index int64) (*models.Division, error) {
 	return division, nil
 }
 
-func DeleteDivision(index int64) error {
-	key := []byte(fmt.Sprintf("division:%d", index))
-	return deleteItem(key)
+func (s *Store) DeleteDivision(index int64) error {
+	key := s.key(fmt.Sprintf("division:%d", index))
+	return s.deleteItemWithHistory(key, "division", index)
 }
 
-func SetTeacher(teacher *models.Teacher) error {
-	key := []byte(fmt.Sprintf("teacher:%d", teacher.Index))
-	return setItem(key, teacher)
+func (s *Store) SetTeacher(teacher *models.Teacher) error {
+	key := s.key(fmt.Sprintf("teacher:%d", teacher.Index))
+	return s.setItemWithHistory(key, "teacher", teacher.Index, teacher)
 }
 
-func GetTeacher(index int64) (*models.Teacher, error) {
-	key := []byte(fmt.Sprintf("teacher:%d", index))
+func (s *Store) GetTeacher(index int64) (*models.Teacher, error) {
+	key := s.key(fmt.Sprintf("teacher:%d", index))
 	teacher := &models.Teacher{}
 	err := getItem(key, teacher)
 	if err != nil {
@@ -82,18 +112,18 @@ func GetTeacher(index int64) (*models.Teacher, error) {
 	return teacher, nil
 }
 
-func DeleteTeacher(index int64) error {
-	key := []byte(fmt.Sprintf("teacher:%d", index))
-	return deleteItem(key)
+func (s *Store) DeleteTeacher(index int64) error {
+	key := s.key(fmt.Sprintf("teacher:%d", index))
+	return s.deleteItemWithHistory(key, "teacher", index)
 }
 
-func SetRoom(room *models.Room) error {
-	key := []byte(fmt.Sprintf("room:%d", room.Index))
-	return setItem(key, room)
+func (s *Store) SetRoom(room *models.Room) error {
+	key := s.key(fmt.Sprintf("room:%d", room.Index))
+	return s.setItemWithHistory(key, "room", room.Index, room)
 }
 
-func GetRoom(index int64) (*models.Room, error) {
-	key := []byte(fmt.Sprintf("room:%d", index))
+func (s *Store) GetRoom(index int64) (*models.Room, error) {
+	key := s.key(fmt.Sprintf("room:%d", index))
 	room := &models.Room{}
 	err := getItem(key, room)
 	if err != nil {
@@ -102,18 +132,50 @@ func GetRoom(index int64) (*models.Room, error) {
 	return room, nil
 }
 
-func DeleteRoom(index int64) error {
-	key := []byte(fmt.Sprintf("room:%d", index))
-	return deleteItem(key)
+func (s *Store) DeleteRoom(index int64) error {
+	key := s.key(fmt.Sprintf("room:%d", index))
+	return s.deleteItemWithHistory(key, "room", index)
 }
 
-func SetMetadata(metadata *models.Metadata) error {
-	key := []byte("metadata")
+// Returns the indexes of the stored items of the resource type, in order
+func (s *Store) GetIndexes(resourceType string) ([]int64, error) {
+	prefix := s.key(resourceType + ":")
+	indexes := []int64{}
+
+	err := view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = prefix
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			index, err := strconv.ParseInt(string(bytes.TrimPrefix(it.Item().Key(), prefix)), 10, 64)
+			if err != nil {
+				continue
+			}
+			indexes = append(indexes, index)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(indexes)
+
+	return indexes, nil
+}
+
+func (s *Store) SetMetadata(metadata *models.Metadata) error {
+	key := s.key("metadata")
 	return setItem(key, metadata)
 }
 
-func GetMetadata() (*models.Metadata, error) {
-	key := []byte("metadata")
+func (s *Store) GetMetadata() (*models.Metadata, error) {
+	key := s.key("metadata")
 	metadata := &models.Metadata{}
 	err := getItem(key, metadata)
 	if err != nil {
@@ -121,3 +183,53 @@ func GetMetadata() (*models.Metadata, error) {
 	}
 	return metadata, nil
 }
+
+func (s *Store) SetPeriods(periods *models.Periods) error {
+	key := s.key("periods")
+	return setItem(key, periods)
+}
+
+func (s *Store) GetPeriods() (*models.Periods, error) {
+	key := s.key("periods")
+	periods := &models.Periods{}
+	err := getItem(key, periods)
+	if err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (s *Store) SetSubstitutions(substitutions *models.Substitutions) error {
+	key := s.key(fmt.Sprintf("substitutions:%s", substitutions.Date))
+	return setItem(key, substitutions)
+}
+
+func (s *Store) GetSubstitutions(date string) (*models.Substitutions, error) {
+	key := s.key(fmt.Sprintf("substitutions:%s", date))
+	substitutions := &models.Substitutions{}
+	err := getItem(key, substitutions)
+	if err != nil {
+		return nil, err
+	}
+	return substitutions, nil
+}
+
+func (s *Store) SetObserverState(url string, state *models.ObserverState) error {
+	key := s.key("observer:" + url)
+	return setItem(key, state)
+}
+
+func (s *Store) GetObserverState(url string) (*models.ObserverState, error) {
+	key := s.key("observer:" + url)
+	state := &models.ObserverState{}
+	err := getItem(key, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *Store) DeleteObserverState(url string) error {
+	key := s.key("observer:" + url)
+	return deleteItem(key)
+}
@@ -3,7 +3,11 @@ package datastore
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
+
+	"smuggr.xyz/goptivum/core/metrics"
 
 	"github.com/dgraph-io/badger/v3"
 	"github.com/dgraph-io/badger/v3/options"
@@ -11,8 +15,34 @@ import (
 
 var DB *badger.DB
 
+// Store reads and writes the data of a single school, its keys
+// are prefixed so several schools can share the database
+type Store struct {
+	Prefix string
+}
+
+func NewStore(prefix string) *Store {
+	return &Store{Prefix: prefix}
+}
+
+func (s *Store) key(key string) []byte {
+	return []byte(s.Prefix + key)
+}
+
+// Runs a read-only transaction, timing it
+func view(fn func(txn *badger.Txn) error) error {
+	defer metrics.ObserveSince(metrics.DatastoreDuration.WithLabelValues("read"), time.Now())
+	return DB.View(fn)
+}
+
+// Runs a read-write transaction, timing it
+func update(fn func(txn *badger.Txn) error) error {
+	defer metrics.ObserveSince(metrics.DatastoreDuration.WithLabelValues("write"), time.Now())
+	return DB.Update(fn)
+}
+
 func Initialize() error {
-	fmt.Println("initializing datastore")
+	slog.Info("initializing datastore")
 
 	opts := badger.DefaultOptions(os.Getenv("DB_FILE_PATH"))
     opts = opts.WithCompression(options.ZSTD)
@@ -27,8 +57,23 @@ func Initialize() error {
 	return nil
 }
 
+// Reports whether the database is open and can be read
+func Ping() error {
+	if DB == nil {
+		return fmt.Errorf("datastore not initialized")
+	}
+
+	if DB.IsClosed() {
+		return fmt.Errorf("datastore closed")
+	}
+
+	return DB.View(func(txn *badger.Txn) error {
+		return nil
+	})
+}
+
 func Cleanup() {
-	fmt.Println("cleaning datastore")
+	slog.Info("cleaning datastore")
 	if err := DB.Close(); err != nil {
 		panic(err)
 	}
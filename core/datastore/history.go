@@ -0,0 +1,135 @@
+// datastore/history.go
+package datastore
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/dgraph-io/badger/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// Versions are stored under "history:<type>:<index>:<unix millis>", the
+// timestamp is zero padded so the keys sort chronologically
+func (s *Store) historyPrefix(resourceType string, index int64) []byte {
+	return s.key(fmt.Sprintf("history:%s:%d:", resourceType, index))
+}
+
+func (s *Store) historyKey(resourceType string, index int64, version int64) []byte {
+	return s.key(fmt.Sprintf("history:%s:%d:%020d", resourceType, index, version))
+}
+
+func parseVersion(key, prefix []byte) (int64, error) {
+	return strconv.ParseInt(string(bytes.TrimPrefix(key, prefix)), 10, 64)
+}
+
+// Returns the latest version and its data
+func getLatestVersion(txn *badger.Txn, prefix []byte) (int64, []byte, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	opts.Prefix = prefix
+
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	seekKey := append(append([]byte{}, prefix...), 0xFF)
+	it.Seek(seekKey)
+	if !it.ValidForPrefix(prefix) {
+		return 0, nil, badger.ErrKeyNotFound
+	}
+
+	version, err := parseVersion(it.Item().Key(), prefix)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	data, err := it.Item().ValueCopy(nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return version, data, nil
+}
+
+// Stores the marshaled item as a new version unless it's equal to the latest
+// one, versions saved within the same millisecond get consecutive timestamps
+func (s *Store) appendHistory(txn *badger.Txn, resourceType string, index int64, item proto.Message, data []byte) error {
+	prefix := s.historyPrefix(resourceType, index)
+
+	version := time.Now().UnixMilli()
+	latestVersion, latest, err := getLatestVersion(txn, prefix)
+	if err != nil && err != badger.ErrKeyNotFound {
+		return err
+	}
+
+	if latest != nil {
+		latestItem := item.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(latest, latestItem); err == nil && proto.Equal(latestItem, item) {
+			return nil
+		}
+		version = max(version, latestVersion+1)
+	}
+
+	return txn.Set(s.historyKey(resourceType, index, version), data)
+}
+
+// Deletes every version of the item
+func (s *Store) deleteHistory(txn *badger.Txn, resourceType string, index int64) error {
+	prefix := s.historyPrefix(resourceType, index)
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = prefix
+
+	it := txn.NewIterator(opts)
+	var keys [][]byte
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		keys = append(keys, it.Item().KeyCopy(nil))
+	}
+	it.Close()
+
+	for _, key := range keys {
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetHistory(resourceType string, index int64) (*models.History, error) {
+	prefix := s.historyPrefix(resourceType, index)
+	history := &models.History{}
+
+	err := view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = prefix
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			version, err := parseVersion(it.Item().Key(), prefix)
+			if err != nil {
+				return err
+			}
+			history.Versions = append(history.Versions, version)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (s *Store) GetVersion(resourceType string, index int64, version int64, item proto.Message) error {
+	return getItem(s.historyKey(resourceType, index, version), item)
+}
@@ -0,0 +1,101 @@
+// datastore/history_test.go
+package datastore
+
+import (
+	"testing"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+func setupStore(t *testing.T, prefix string) *Store {
+	t.Helper()
+
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("error opening datastore: %v", err)
+	}
+	DB = db
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return NewStore(prefix)
+}
+
+func TestHistoryVersions(t *testing.T) {
+	store := setupStore(t, "zsem:")
+
+	// Saved within the same millisecond, every change still gets its own version
+	for _, fullName := range []string{"technikum", "technikum", "liceum", "technikum"} {
+		if err := store.SetDivision(&models.Division{Index: 1, Designator: "1A", FullName: fullName}); err != nil {
+			t.Fatalf("error storing division: %v", err)
+		}
+	}
+
+	history, err := store.GetHistory("division", 1)
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if len(history.Versions) != 3 {
+		t.Fatalf("history: got %d version(s), want 3", len(history.Versions))
+	}
+	for i := 1; i < len(history.Versions); i++ {
+		if history.Versions[i] <= history.Versions[i-1] {
+			t.Errorf("history: versions %v aren't increasing", history.Versions)
+		}
+	}
+
+	division := &models.Division{}
+	if err := store.GetVersion("division", 1, history.Versions[1], division); err != nil {
+		t.Fatalf("error reading version: %v", err)
+	}
+	if division.FullName != "liceum" {
+		t.Errorf("version: got %q, want \"liceum\"", division.FullName)
+	}
+}
+
+func TestDeleteDropsHistory(t *testing.T) {
+	store := setupStore(t, "zsem:")
+	other := NewStore("lo1:")
+
+	for _, s := range []*Store{store, other} {
+		if err := s.SetTeacher(&models.Teacher{Index: 1, Designator: "JK"}); err != nil {
+			t.Fatalf("error storing teacher: %v", err)
+		}
+		if err := s.SetTeacher(&models.Teacher{Index: 10, Designator: "AN"}); err != nil {
+			t.Fatalf("error storing teacher: %v", err)
+		}
+	}
+
+	if err := store.DeleteTeacher(1); err != nil {
+		t.Fatalf("error deleting teacher: %v", err)
+	}
+
+	if _, err := store.GetTeacher(1); err != badger.ErrKeyNotFound {
+		t.Errorf("deleted teacher: got error %v, want %v", err, badger.ErrKeyNotFound)
+	}
+
+	tests := []struct {
+		store    *Store
+		index    int64
+		versions int
+	}{
+		{store, 1, 0},
+		// Indexes sharing the digits keep their history
+		{store, 10, 1},
+		// So do the other schools
+		{other, 1, 1},
+	}
+
+	for _, tt := range tests {
+		history, err := tt.store.GetHistory("teacher", tt.index)
+		if err != nil {
+			t.Fatalf("error reading history: %v", err)
+		}
+		if len(history.Versions) != tt.versions {
+			t.Errorf("%steacher:%d history: got %d version(s), want %d", tt.store.Prefix, tt.index, len(history.Versions), tt.versions)
+		}
+	}
+}
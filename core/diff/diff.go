@@ -0,0 +1,192 @@
+// diff/diff.go
+package diff
+
+import (
+	"errors"
+	"sort"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Versions stored before the period numbers were scraped only have the
+// position of each lesson group, diffing them would move every lesson
+var ErrIncomparable = errors.New("schedules with and without period numbers can't be compared")
+
+type ChangeType string
+
+const (
+	LessonAdded    ChangeType = "lesson_added"
+	LessonRemoved  ChangeType = "lesson_removed"
+	LessonMoved    ChangeType = "lesson_moved"
+	TeacherChanged ChangeType = "teacher_changed"
+	RoomChanged    ChangeType = "room_changed"
+)
+
+func (t ChangeType) String() string {
+	return string(t)
+}
+
+type slot struct {
+	Day    int64
+	Period int64
+}
+
+type entry struct {
+	Slot   slot
+	Lesson *models.Lesson
+}
+
+func flatten(schedule *models.Schedule) []*entry {
+	var entries []*entry
+	if schedule == nil {
+		return entries
+	}
+
+	for day, scheduleDay := range schedule.ScheduleDays {
+		for _, lessonGroup := range scheduleDay.LessonGroups {
+			for _, lesson := range lessonGroup.Lessons {
+				entries = append(entries, &entry{
+					Slot:   slot{Day: int64(day), Period: lessonGroup.Period},
+					Lesson: lesson,
+				})
+			}
+		}
+	}
+
+	return entries
+}
+
+// The same lesson of the same group, the teacher and room may differ
+func sameLesson(a, b *models.Lesson) bool {
+	return a.FullName == b.FullName &&
+		a.GroupLabel == b.GroupLabel &&
+		a.DivisionDesignator == b.DivisionDesignator
+}
+
+func sameResource(aIndex, bIndex int64, aDesignator, bDesignator string) bool {
+	if aIndex != 0 && bIndex != 0 {
+		return aIndex == bIndex
+	}
+
+	return aDesignator == bDesignator
+}
+
+func sameTeacher(a, b *models.Lesson) bool {
+	return sameResource(a.TeacherIndex, b.TeacherIndex, a.TeacherDesignator, b.TeacherDesignator)
+}
+
+func sameRoom(a, b *models.Lesson) bool {
+	return sameResource(a.RoomIndex, b.RoomIndex, a.RoomDesignator, b.RoomDesignator)
+}
+
+// Pairs up entries of both lists that satisfy the predicate, matched
+// entries are removed from the lists
+func match(from, to []*entry, predicate func(a, b *entry) bool, onMatch func(a, b *entry)) ([]*entry, []*entry) {
+	var remainingFrom []*entry
+	matchedTo := make([]bool, len(to))
+
+	for _, a := range from {
+		matched := false
+		for i, b := range to {
+			if matchedTo[i] || !predicate(a, b) {
+				continue
+			}
+
+			onMatch(a, b)
+			matchedTo[i] = true
+			matched = true
+			break
+		}
+
+		if !matched {
+			remainingFrom = append(remainingFrom, a)
+		}
+	}
+
+	var remainingTo []*entry
+	for i, b := range to {
+		if !matchedTo[i] {
+			remainingTo = append(remainingTo, b)
+		}
+	}
+
+	return remainingFrom, remainingTo
+}
+
+// Schedules computes the changes needed to turn the from schedule into the to schedule,
+// a nil from schedule is empty
+func Schedules(from, to *models.Schedule) ([]*models.ScheduleChange, error) {
+	if from != nil && to != nil && from.HasPeriods != to.HasPeriods {
+		return nil, ErrIncomparable
+	}
+
+	var changes []*models.ScheduleChange
+
+	fromEntries := flatten(from)
+	toEntries := flatten(to)
+
+	// Unchanged lessons
+	fromEntries, toEntries = match(fromEntries, toEntries, func(a, b *entry) bool {
+		return a.Slot == b.Slot && proto.Equal(a.Lesson, b.Lesson)
+	}, func(a, b *entry) {})
+
+	// Same lesson in the same slot, but with a different teacher or room
+	fromEntries, toEntries = match(fromEntries, toEntries, func(a, b *entry) bool {
+		return a.Slot == b.Slot && sameLesson(a.Lesson, b.Lesson)
+	}, func(a, b *entry) {
+		if !sameTeacher(a.Lesson, b.Lesson) {
+			changes = append(changes, newChange(TeacherChanged, a, b))
+		}
+		if !sameRoom(a.Lesson, b.Lesson) {
+			changes = append(changes, newChange(RoomChanged, a, b))
+		}
+	})
+
+	// Same lesson taught by the same teacher, but in a different slot
+	fromEntries, toEntries = match(fromEntries, toEntries, func(a, b *entry) bool {
+		return sameLesson(a.Lesson, b.Lesson) && sameTeacher(a.Lesson, b.Lesson)
+	}, func(a, b *entry) {
+		changes = append(changes, newChange(LessonMoved, a, b))
+	})
+
+	for _, a := range fromEntries {
+		changes = append(changes, newChange(LessonRemoved, a, nil))
+	}
+
+	for _, b := range toEntries {
+		changes = append(changes, newChange(LessonAdded, nil, b))
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Day != changes[j].Day {
+			return changes[i].Day < changes[j].Day
+		}
+		return changes[i].Period < changes[j].Period
+	})
+
+	return changes, nil
+}
+
+func newChange(changeType ChangeType, from, to *entry) *models.ScheduleChange {
+	change := &models.ScheduleChange{
+		Type: changeType.String(),
+	}
+
+	if from != nil {
+		change.Day = from.Slot.Day
+		change.Period = from.Slot.Period
+		change.PreviousDay = from.Slot.Day
+		change.PreviousPeriod = from.Slot.Period
+		change.PreviousLesson = from.Lesson
+	}
+
+	if to != nil {
+		change.Day = to.Slot.Day
+		change.Period = to.Slot.Period
+		change.Lesson = to.Lesson
+	}
+
+	return change
+}
@@ -0,0 +1,188 @@
+// diff/diff_test.go
+package diff
+
+import (
+	"testing"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+type placed struct {
+	Day    int
+	Period int64
+	Lesson *models.Lesson
+}
+
+func newSchedule(lessons ...placed) *models.Schedule {
+	schedule := &models.Schedule{HasPeriods: true}
+	for i := 0; i < 5; i++ {
+		schedule.ScheduleDays = append(schedule.ScheduleDays, &models.ScheduleDay{})
+	}
+
+	for _, p := range lessons {
+		day := schedule.ScheduleDays[p.Day]
+		day.LessonGroups = append(day.LessonGroups, &models.LessonGroup{
+			Period:  p.Period,
+			Lessons: []*models.Lesson{p.Lesson},
+		})
+	}
+
+	return schedule
+}
+
+func math() *models.Lesson {
+	return &models.Lesson{FullName: "matematyka", TeacherIndex: 1, TeacherDesignator: "JK", RoomIndex: 1, RoomDesignator: "101"}
+}
+
+func physics() *models.Lesson {
+	return &models.Lesson{FullName: "fizyka", TeacherIndex: 2, TeacherDesignator: "AN", RoomIndex: 2, RoomDesignator: "105"}
+}
+
+type change struct {
+	Type           ChangeType
+	Day, Period    int64
+	PreviousDay    int64
+	PreviousPeriod int64
+}
+
+func TestSchedules(t *testing.T) {
+	withTeacher := math()
+	withTeacher.TeacherIndex, withTeacher.TeacherDesignator = 3, "PW"
+
+	withRoom := math()
+	withRoom.RoomIndex, withRoom.RoomDesignator = 3, "204"
+
+	withBoth := math()
+	withBoth.TeacherIndex, withBoth.RoomIndex = 3, 3
+
+	// Lessons scraped without indexes are compared by designator
+	unindexed := &models.Lesson{FullName: "matematyka", TeacherDesignator: "JK", RoomDesignator: "101"}
+	unindexedRoom := &models.Lesson{FullName: "matematyka", TeacherDesignator: "JK", RoomDesignator: "204"}
+
+	tests := []struct {
+		name     string
+		from, to *models.Schedule
+		expected []change
+	}{
+		{
+			name:     "unchanged",
+			from:     newSchedule(placed{0, 1, math()}, placed{1, 2, physics()}),
+			to:       newSchedule(placed{0, 1, math()}, placed{1, 2, physics()}),
+			expected: nil,
+		},
+		{
+			name:     "added",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{0, 1, math()}, placed{2, 3, physics()}),
+			expected: []change{{LessonAdded, 2, 3, 0, 0}},
+		},
+		{
+			name:     "removed",
+			from:     newSchedule(placed{0, 1, math()}, placed{2, 3, physics()}),
+			to:       newSchedule(placed{0, 1, math()}),
+			expected: []change{{LessonRemoved, 2, 3, 2, 3}},
+		},
+		{
+			name:     "moved",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{3, 5, math()}),
+			expected: []change{{LessonMoved, 3, 5, 0, 1}},
+		},
+		{
+			name:     "teacher changed",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{0, 1, withTeacher}),
+			expected: []change{{TeacherChanged, 0, 1, 0, 1}},
+		},
+		{
+			name:     "room changed",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{0, 1, withRoom}),
+			expected: []change{{RoomChanged, 0, 1, 0, 1}},
+		},
+		{
+			name:     "teacher and room changed",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{0, 1, withBoth}),
+			expected: []change{{TeacherChanged, 0, 1, 0, 1}, {RoomChanged, 0, 1, 0, 1}},
+		},
+		{
+			name:     "room changed by designator",
+			from:     newSchedule(placed{0, 1, unindexed}),
+			to:       newSchedule(placed{0, 1, unindexedRoom}),
+			expected: []change{{RoomChanged, 0, 1, 0, 1}},
+		},
+		{
+			// A different teacher in a different slot isn't the same lesson moved
+			name:     "replaced",
+			from:     newSchedule(placed{0, 1, math()}),
+			to:       newSchedule(placed{1, 1, withTeacher}),
+			expected: []change{{LessonRemoved, 0, 1, 0, 1}, {LessonAdded, 1, 1, 0, 0}},
+		},
+		{
+			// Optivum's lesson 0 is a period like any other
+			name:     "moved from lesson 0",
+			from:     newSchedule(placed{0, 0, math()}, placed{0, 1, physics()}),
+			to:       newSchedule(placed{0, 1, physics()}, placed{0, 2, math()}),
+			expected: []change{{LessonMoved, 0, 2, 0, 0}},
+		},
+		{
+			name:     "from nothing",
+			from:     nil,
+			to:       newSchedule(placed{4, 8, physics()}),
+			expected: []change{{LessonAdded, 4, 8, 0, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Schedules(tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("error diffing schedules: %v", err)
+			}
+			if len(changes) != len(tt.expected) {
+				t.Fatalf("got %d change(s), want %d: %v", len(changes), len(tt.expected), changes)
+			}
+
+			for i, expected := range tt.expected {
+				got := change{
+					Type:           ChangeType(changes[i].Type),
+					Day:            changes[i].Day,
+					Period:         changes[i].Period,
+					PreviousDay:    changes[i].PreviousDay,
+					PreviousPeriod: changes[i].PreviousPeriod,
+				}
+				if got != expected {
+					t.Errorf("change %d: got %+v, want %+v", i, got, expected)
+				}
+
+				if expected.Type != LessonRemoved && changes[i].Lesson == nil {
+					t.Errorf("change %d: missing the new lesson", i)
+				}
+				if expected.Type != LessonAdded && changes[i].PreviousLesson == nil {
+					t.Errorf("change %d: missing the previous lesson", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulesWithoutPeriods(t *testing.T) {
+	legacy := newSchedule(placed{0, 0, math()}, placed{0, 0, physics()})
+	legacy.HasPeriods = false
+
+	if _, err := Schedules(legacy, newSchedule(placed{0, 1, math()}, placed{0, 2, physics()})); err != ErrIncomparable {
+		t.Errorf("legacy to new: got error %v, want %v", err, ErrIncomparable)
+	}
+	if _, err := Schedules(newSchedule(placed{0, 1, math()}), legacy); err != ErrIncomparable {
+		t.Errorf("new to legacy: got error %v, want %v", err, ErrIncomparable)
+	}
+
+	changes, err := Schedules(legacy, legacy)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("legacy to legacy: got %v, %v, want no changes", changes, err)
+	}
+	if _, err := Schedules(nil, legacy); err != nil {
+		t.Errorf("nothing to legacy: got error %v", err)
+	}
+}
@@ -4,14 +4,21 @@ package hub
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"crypto/tls"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"smuggr.xyz/goptivum/core/metrics"
 	"smuggr.xyz/goptivum/core/observer"
+	"smuggr.xyz/goptivum/core/source"
 )
 
+// Checks that leave the state unchanged only save when it was checked
+// at most this often, a restart then rechecks the page a bit early
+const stateSaveInterval = 1 * time.Minute
+
 type Hub struct {
 	observers   map[int64]*observer.Observer
 	addCh       chan *observer.Observer
@@ -22,23 +29,34 @@ type Hub struct {
 	mu          sync.RWMutex
 	quitCh      chan struct{}
 	client      *http.Client
+	policy      *Policy
+	store       observer.StateStore
+
+	checks       atomic.Int64
+	failedChecks atomic.Int64
+	lastSuccess  atomic.Int64
+	failing      map[int64]bool
+	failingMu    sync.Mutex
+	handling     map[int64]int
+	handlingMu   sync.Mutex
+
+	school   string
+	resource string
 }
 
-func NewHub(workerCount int64, ignoreCertificates bool) *Hub {
-	transport := &http.Transport{
-		MaxIdleConns:        2000,
-		MaxIdleConnsPerHost: 1000,
-		IdleConnTimeout:     90 * time.Second,
-		TLSClientConfig:    &tls.Config{
-			// #nosec G402
-			InsecureSkipVerify: ignoreCertificates,
-		},
-	}
+// Stats is a snapshot of the observers of the hub and the outcome of their checks
+type Stats struct {
+	Observers        int64
+	FailingObservers int64
+	Checks           int64
+	FailedChecks     int64
+	LastSuccess      time.Time
+}
 
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
-	}
+// A nil policy polls every observer at its fixed interval, a nil store
+// makes every observer start from scratch
+func NewHub(workerCount int64, ignoreCertificates bool, policy *Policy, store observer.StateStore) *Hub {
+	client := source.NewClient(ignoreCertificates, 30*time.Second)
 
 	return &Hub{
 		observers:   make(map[int64]*observer.Observer),
@@ -48,10 +66,26 @@ func NewHub(workerCount int64, ignoreCertificates bool) *Hub {
 		workerCount: workerCount,
 		quitCh:      make(chan struct{}),
 		client:      client,
+		policy:      policy,
+		store:       store,
+		failing:     make(map[int64]bool),
+		handling:    make(map[int64]int),
 	}
 }
 
+// Sets the labels the hub's metrics are reported under
+func (h *Hub) SetLabels(school string, resource string) {
+	h.school = school
+	h.resource = resource
+}
+
+func (h *Hub) logger() *slog.Logger {
+	return slog.With("school", h.school, "resource", h.resource)
+}
+
 func (h *Hub) Start() {
+	metrics.HubWorkers.WithLabelValues(h.school, h.resource).Set(float64(h.workerCount))
+
 	for i := int64(0); i < h.workerCount; i++ {
 		h.wg.Add(1)
 		go h.worker(i)
@@ -60,19 +94,61 @@ func (h *Hub) Start() {
 	h.wg.Add(1)
 	go h.scheduler()
 
-	fmt.Printf("hub started with %d worker(s)\n", h.workerCount)
+	h.logger().Info("hub started", "workers", h.workerCount)
 }
 
 func (h *Hub) Stop() {
 	close(h.quitCh)
 	h.wg.Wait()
-	fmt.Println("hub has been stopped gracefully")
+	h.logger().Info("hub has been stopped gracefully")
 }
 
+// Restores the state of the observer before adding it, so that it's checked
+// when it's due and only reports a change if the page changed since the last check
 func (h *Hub) AddObserver(o *observer.Observer) {
+	h.restoreState(o)
 	h.addCh <- o
 }
 
+func (h *Hub) restoreState(o *observer.Observer) {
+	if h.store == nil {
+		o.SetState(nil)
+		return
+	}
+
+	state, err := h.store.LoadState(o.URL)
+	if err != nil || state == nil {
+		o.SetState(nil)
+		return
+	}
+
+	o.SetState(state)
+
+	o.Mu.Lock()
+	o.NextRun = state.LastChecked.Add(o.Interval)
+	o.Mu.Unlock()
+}
+
+// Saves the state of the observer when its hash or validators changed,
+// or when the last saved check is too old
+func (h *Hub) saveState(o *observer.Observer) {
+	if h.store == nil {
+		return
+	}
+
+	state, ok := o.StateToSave(stateSaveInterval)
+	if !ok {
+		return
+	}
+
+	if err := h.store.SaveState(o.URL, state); err != nil {
+		h.logger().Error("error saving observer state", "index", o.Index, "url", o.URL, "error", err)
+		return
+	}
+
+	o.MarkSaved(state)
+}
+
 func (h *Hub) RemoveObserver(index int64) {
 	h.removeCh <- index
 }
@@ -87,40 +163,144 @@ func (h *Hub) GetAllObservers(ignoreFirst bool) map[int64]*observer.Observer {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 	copyMap := make(map[int64]*observer.Observer, len(h.observers))
-	if ignoreFirst {
-		delete(copyMap, 0)
-	}
 	for k, v := range h.observers {
 		copyMap[k] = v
 	}
+	// The list observer is kept under index 0
+	if ignoreFirst {
+		delete(copyMap, 0)
+	}
 	return copyMap
 }
 
+// Observers whose last check failed count as failing
+func (h *Hub) Stats() Stats {
+	stats := Stats{
+		Checks:       h.checks.Load(),
+		FailedChecks: h.failedChecks.Load(),
+	}
+
+	if lastSuccess := h.lastSuccess.Load(); lastSuccess != 0 {
+		stats.LastSuccess = time.UnixMilli(lastSuccess)
+	}
+
+	h.mu.RLock()
+	stats.Observers = int64(len(h.observers))
+	h.mu.RUnlock()
+
+	// Observers are locked while they're being checked, so the
+	// failing ones are tracked by the hub instead of read from them
+	h.failingMu.Lock()
+	stats.FailingObservers = int64(len(h.failing))
+	h.failingMu.Unlock()
+
+	return stats
+}
+
+func (h *Hub) setFailing(index int64, failing bool) {
+	h.failingMu.Lock()
+	defer h.failingMu.Unlock()
+
+	if failing {
+		h.failing[index] = true
+	} else {
+		delete(h.failing, index)
+	}
+}
+
+// Counts the callbacks of the observer that are running, its state
+// isn't saved until they're done
+func (h *Hub) setHandling(index int64, delta int) {
+	h.handlingMu.Lock()
+	defer h.handlingMu.Unlock()
+
+	h.handling[index] += delta
+	if h.handling[index] <= 0 {
+		delete(h.handling, index)
+	}
+}
+
+func (h *Hub) isHandling(index int64) bool {
+	h.handlingMu.Lock()
+	defer h.handlingMu.Unlock()
+	return h.handling[index] > 0
+}
+
+// Checks the observer and records the outcome, reports whether its page changed
+func (h *Hub) checkObserver(log *slog.Logger, o *observer.Observer) bool {
+	busyWorkers := metrics.HubBusyWorkers.WithLabelValues(h.school, h.resource)
+	busyWorkers.Inc()
+	defer busyWorkers.Dec()
+
+	metrics.HubQueueDepth.WithLabelValues(h.school, h.resource).Set(float64(len(h.tasksCh)))
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	changed, err := o.Check(ctx, h.client)
+	cancel()
+	metrics.ObserveSince(metrics.ObserverFetchDuration.WithLabelValues(h.school, h.resource), start)
+
+	h.checks.Add(1)
+	h.setFailing(o.Index, err != nil)
+	if err != nil {
+		h.failedChecks.Add(1)
+		metrics.ObserverErrors.WithLabelValues(h.school, h.resource).Inc()
+		log.Warn("error checking observer", "index", o.Index, "url", o.URL, "error", err)
+	} else {
+		h.lastSuccess.Store(time.Now().UnixMilli())
+	}
+
+	if changed {
+		metrics.ObserverChanges.WithLabelValues(h.school, h.resource).Inc()
+	}
+
+	return changed
+}
+
 func (h *Hub) worker(id int64) {
 	defer h.wg.Done()
-	fmt.Printf("worker %d started\n", id)
+	log := h.logger().With("worker", id)
+	log.Debug("worker started")
 
 	for {
 		select {
 		case o := <-h.tasksCh:
-			fmt.Printf("worker %d: processing observer with URL: %s\n", id, o.URL)
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			log.Debug("processing observer", "index", o.Index, "url", o.URL)
+			changed := h.checkObserver(log, o)
 
-			// It's safe to defer cancel here because it's called before the next iteration
-			// However, to avoid multiple defers in a loop, I'll call cancel manually
-			changed := o.CompareHashWithClient(ctx, h.client)
-			cancel()
+			if h.policy != nil {
+				now := time.Now()
+				o.Mu.Lock()
+				o.NextRun = now.Add(h.policy.NextInterval(o, now))
+				o.Mu.Unlock()
+			}
 
 			if changed {
 				if o.Callback != nil {
-					go o.Callback()
+					// The new hash is saved once the change has been handled,
+					// otherwise a restart in between would miss it
+					h.setHandling(o.Index, 1)
+					go func(o *observer.Observer) {
+						defer h.setHandling(o.Index, -1)
+
+						if err := o.Callback(); err != nil {
+							// The next check fetches the page again and retries
+							log.Error("error handling observer change", "index", o.Index, "url", o.URL, "error", err)
+							o.Invalidate()
+							return
+						}
+						h.saveState(o)
+					}(o)
 				} else {
-					fmt.Printf("worker %d: no callback for observer with URL: %s\n", id, o.URL)
+					log.Warn("no callback for observer", "index", o.Index, "url", o.URL)
+					h.saveState(o)
 				}
+			} else if !h.isHandling(o.Index) {
+				h.saveState(o)
 			}
 
 		case <-h.quitCh:
-			fmt.Printf("stopping worker of id %d\n", id)
+			log.Debug("stopping worker")
 			return
 		}
 	}
@@ -136,32 +316,34 @@ func (h *Hub) scheduler() {
 		case o := <-h.addCh:
 			h.mu.Lock()
 			if _, exists := h.observers[o.Index]; exists {
-				fmt.Printf("observer of index %d already exists\n", o.Index)
+				h.logger().Warn("observer already exists", "index", o.Index)
 			} else {
-				o.Mu.Lock()
-				o.Hash = ""
-				o.Mu.Unlock()
 				h.observers[o.Index] = o
-				fmt.Printf("added observer of index: %d\n", o.Index)
+				h.logger().Debug("added observer", "index", o.Index, "url", o.URL)
 			}
 			h.mu.Unlock()
 
 		case _index := <-h.removeCh:
 			index, ok := _index.(int64)
 			if !ok {
-				fmt.Printf("invalid type for index: %T\n", _index)
+				h.logger().Error("invalid type for index", "type", fmt.Sprintf("%T", _index))
 				continue
 			}
 
 			h.mu.Lock()
 			if o, exists := h.observers[index]; exists {
-				o.Mu.Lock()
 				delete(h.observers, index)
-				fmt.Printf("removed observer of index: %d\n", index)
-				o.Hash = ""
-				o.Mu.Unlock()
+				h.setFailing(index, false)
+				h.logger().Debug("removed observer", "index", index, "url", o.URL)
+				o.SetState(nil)
+
+				if h.store != nil {
+					if err := h.store.DeleteState(o.URL); err != nil {
+						h.logger().Error("error deleting observer state", "index", index, "url", o.URL, "error", err)
+					}
+				}
 			} else {
-				fmt.Printf("observer of index %d does not exist\n", index)
+				h.logger().Warn("observer does not exist", "index", index)
 			}
 			h.mu.Unlock()
 
@@ -175,15 +357,17 @@ func (h *Hub) scheduler() {
 					select {
 					case h.tasksCh <- o:
 					case <-time.After(10 * time.Second):
-						fmt.Printf("timed out sending observer with URL: %s to tasksCh\n", o.URL)
+						metrics.HubDroppedTasks.WithLabelValues(h.school, h.resource).Inc()
+						h.logger().Warn("timed out sending observer to tasksCh", "index", o.Index, "url", o.URL)
 					}
 				}
 				o.Mu.Unlock()
 			}
 			h.mu.RUnlock()
+			metrics.HubQueueDepth.WithLabelValues(h.school, h.resource).Set(float64(len(h.tasksCh)))
 
 		case <-h.quitCh:
-			fmt.Println("stopping scheduler")
+			h.logger().Debug("stopping scheduler")
 			return
 		}
 	}
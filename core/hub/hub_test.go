@@ -0,0 +1,165 @@
+// hub/hub_test.go
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/core/observer"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type memoryStore struct {
+	mu     sync.Mutex
+	states map[string]observer.State
+	saves  int
+}
+
+func (m *memoryStore) LoadState(url string) (*observer.State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[url]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (m *memoryStore) SaveState(url string, state *observer.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[url] = *state
+	m.saves++
+	return nil
+}
+
+func (m *memoryStore) DeleteState(url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.states, url)
+	return nil
+}
+
+func (m *memoryStore) get(url string) (observer.State, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[url], m.saves
+}
+
+func newPage(t *testing.T, content *string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", fmt.Sprintf("%q", *content))
+		fmt.Fprintf(w, "<html><body><p>%s</p></body></html>", *content)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func extractText(doc *goquery.Document) string {
+	return doc.Find("p").Text()
+}
+
+// Runs a single check the way a worker does and waits for the callback
+func runCheck(h *Hub, o *observer.Observer) {
+	done := make(chan struct{})
+	callback := o.Callback
+	o.Callback = func() error {
+		defer close(done)
+		return callback()
+	}
+
+	h.tasksCh <- o
+	select {
+	case <-done:
+		// Lets the worker finish handling the outcome
+		time.Sleep(50 * time.Millisecond)
+	case <-time.After(200 * time.Millisecond):
+	}
+	o.Callback = callback
+}
+
+func TestFailedCallbackKeepsState(t *testing.T) {
+	content := "first"
+	server := newPage(t, &content)
+	store := &memoryStore{states: make(map[string]observer.State)}
+
+	h := NewHub(1, false, nil, store)
+	for i := int64(0); i < h.workerCount; i++ {
+		h.wg.Add(1)
+		go h.worker(i)
+	}
+	defer func() {
+		close(h.quitCh)
+		h.wg.Wait()
+	}()
+
+	var fail bool
+	o := observer.NewObserver(1, server.URL, time.Hour, extractText, func() error {
+		if fail {
+			return errors.New("datastore unavailable")
+		}
+		return nil
+	})
+	h.restoreState(o)
+
+	runCheck(h, o)
+	saved, _ := store.get(server.URL)
+	if saved.Hash == "" || saved.ETag != `"first"` {
+		t.Fatalf("first check: got %+v, want the hash and etag saved", saved)
+	}
+
+	content = "second"
+	fail = true
+	runCheck(h, o)
+
+	if after, _ := store.get(server.URL); after != saved {
+		t.Errorf("failed callback: saved state changed from %+v to %+v", saved, after)
+	}
+	if state := o.GetState(); state.Hash != "" || state.ETag != "" || state.LastModified != "" {
+		t.Errorf("failed callback: got %+v, want the hash and validators cleared", state)
+	}
+
+	// The page is scraped again even though it hasn't changed since
+	fail = false
+	runCheck(h, o)
+	if after, _ := store.get(server.URL); after.Hash == saved.Hash || after.ETag != `"second"` {
+		t.Errorf("retried check: got %+v, want the new hash and etag saved", after)
+	}
+}
+
+func TestUnchangedChecksAreThrottled(t *testing.T) {
+	content := "same"
+	server := newPage(t, &content)
+	store := &memoryStore{states: make(map[string]observer.State)}
+
+	h := NewHub(1, false, nil, store)
+	h.wg.Add(1)
+	go h.worker(0)
+	defer func() {
+		close(h.quitCh)
+		h.wg.Wait()
+	}()
+
+	o := observer.NewObserver(1, server.URL, time.Hour, extractText, func() error { return nil })
+	h.restoreState(o)
+
+	for i := 0; i < 5; i++ {
+		runCheck(h, o)
+	}
+
+	if _, saves := store.get(server.URL); saves != 1 {
+		t.Errorf("got %d saves, want only the first check saved", saves)
+	}
+}
@@ -0,0 +1,170 @@
+// hub/polling.go
+package hub
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"smuggr.xyz/goptivum/core/observer"
+)
+
+const dateLayout = "2006-01-02"
+
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Window is a span of time (days of the week, dates, hours of the day or
+// a combination of them) during which observers are polled at their own interval
+type Window struct {
+	Name     string
+	Days     []time.Weekday
+	Dates    []DateRange
+	Start    int64
+	End      int64
+	HasHours bool
+	Interval time.Duration
+}
+
+// Policy decides when an observer is checked next based on how its previous
+// checks went, a nil policy keeps the fixed interval of the observer. A zero
+// MaxInterval leaves the intervals uncapped
+type Policy struct {
+	MaxInterval   time.Duration
+	BackoffFactor float64
+	StableAfter   int64
+	StableFactor  float64
+	Jitter        float64
+	Windows       []*Window
+	Location      *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func ParseWeekday(s string) (time.Weekday, error) {
+	weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("invalid day of the week: %s", s)
+	}
+	return weekday, nil
+}
+
+// Parses a single date (YYYY-MM-DD) or an inclusive range of dates (YYYY-MM-DD/YYYY-MM-DD)
+func ParseDateRange(s string, location *time.Location) (DateRange, error) {
+	start, end, isRange := strings.Cut(s, "/")
+	if !isRange {
+		end = start
+	}
+
+	startDate, err := time.ParseInLocation(dateLayout, strings.TrimSpace(start), location)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid date: %w", err)
+	}
+
+	endDate, err := time.ParseInLocation(dateLayout, strings.TrimSpace(end), location)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid date: %w", err)
+	}
+
+	if endDate.Before(startDate) {
+		return DateRange{}, fmt.Errorf("date range ends before it starts: %s", s)
+	}
+
+	return DateRange{Start: startDate, End: endDate.AddDate(0, 0, 1)}, nil
+}
+
+func (w *Window) Contains(t time.Time) bool {
+	if len(w.Days) > 0 {
+		found := false
+		for _, day := range w.Days {
+			if t.Weekday() == day {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(w.Dates) > 0 {
+		found := false
+		for _, dateRange := range w.Dates {
+			if !t.Before(dateRange.Start) && t.Before(dateRange.End) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if w.HasHours {
+		minutes := int64(t.Hour()*60 + t.Minute())
+		// Windows like 22:00 - 06:00 wrap around midnight
+		if w.Start <= w.End {
+			return w.Start <= minutes && minutes < w.End
+		}
+		return minutes >= w.Start || minutes < w.End
+	}
+
+	return true
+}
+
+// Returns the first window containing the given time, nil if there's none
+func (p *Policy) window(t time.Time) *Window {
+	if p.Location != nil {
+		t = t.In(p.Location)
+	}
+
+	for _, window := range p.Windows {
+		if window.Contains(t) {
+			return window
+		}
+	}
+
+	return nil
+}
+
+// NextInterval computes the delay before the next check of the observer:
+// failing observers back off exponentially, observers that haven't changed for
+// a while slow down, and windows replace the base interval, all with jitter
+func (p *Policy) NextInterval(o *observer.Observer, now time.Time) time.Duration {
+	base := o.Interval
+	if window := p.window(now); window != nil && window.Interval > 0 {
+		base = window.Interval
+	}
+
+	interval := float64(base)
+
+	if o.Failures > 0 && p.BackoffFactor > 1 {
+		interval *= math.Pow(p.BackoffFactor, float64(o.Failures))
+	} else if p.StableAfter > 0 && o.Unchanged > p.StableAfter && p.StableFactor > 1 {
+		interval *= math.Pow(p.StableFactor, float64(o.Unchanged-p.StableAfter))
+	}
+
+	// Without a max interval the backoff and slowdown aren't capped
+	if p.MaxInterval > 0 {
+		interval = min(interval, float64(max(p.MaxInterval, base)))
+	}
+
+	if p.Jitter > 0 {
+		// #nosec G404
+		interval *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return max(time.Duration(interval), time.Second)
+}
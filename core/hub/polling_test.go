@@ -0,0 +1,141 @@
+// hub/polling_test.go
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/core/observer"
+)
+
+func mustDateRange(t *testing.T, s string) DateRange {
+	t.Helper()
+
+	dateRange, err := ParseDateRange(s, time.UTC)
+	if err != nil {
+		t.Fatalf("error parsing date range %s: %v", s, err)
+	}
+	return dateRange
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestWindowContains(t *testing.T) {
+	night := &Window{Start: 22 * 60, End: 6 * 60, HasHours: true}
+	lessons := &Window{Days: []time.Weekday{time.Monday, time.Tuesday}, Start: 8 * 60, End: 15 * 60, HasHours: true}
+	weekend := &Window{Days: []time.Weekday{time.Saturday, time.Sunday}}
+	breakWindow := &Window{Dates: []DateRange{mustDateRange(t, "2030-12-23/2031-01-06"), mustDateRange(t, "2031-02-14")}}
+	breakNights := &Window{Dates: breakWindow.Dates, Start: 22 * 60, End: 6 * 60, HasHours: true}
+
+	tests := []struct {
+		name     string
+		window   *Window
+		time     string
+		expected bool
+	}{
+		{"night before midnight", night, "2030-03-04 23:30", true},
+		{"night after midnight", night, "2030-03-05 05:59", true},
+		{"night ends", night, "2030-03-05 06:00", false},
+		{"night starts", night, "2030-03-04 22:00", true},
+		{"daytime", night, "2030-03-04 12:00", false},
+		{"lessons", lessons, "2030-03-04 08:00", true},
+		{"lessons end", lessons, "2030-03-04 15:00", false},
+		{"lessons on another day", lessons, "2030-03-06 10:00", false},
+		{"saturday", weekend, "2030-03-09 12:00", true},
+		{"friday", weekend, "2030-03-08 23:59", false},
+		{"first day of the break", breakWindow, "2030-12-23 00:00", true},
+		{"across the new year", breakWindow, "2031-01-01 12:00", true},
+		{"last day of the break", breakWindow, "2031-01-06 23:59", true},
+		{"after the break", breakWindow, "2031-01-07 00:00", false},
+		{"single date", breakWindow, "2031-02-14 09:00", true},
+		{"break night across midnight", breakNights, "2030-12-31 23:00", true},
+		{"break night after the break", breakNights, "2031-01-07 01:00", false},
+		{"break day", breakNights, "2030-12-31 12:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(at(tt.time)); got != tt.expected {
+				t.Errorf("Contains(%s) = %v, want %v", tt.time, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	for _, s := range []string{"2030-13-01", "2030-01-06/2030-01-01", "tomorrow"} {
+		if _, err := ParseDateRange(s, time.UTC); err == nil {
+			t.Errorf("ParseDateRange(%q): expected an error", s)
+		}
+	}
+}
+
+func TestNextInterval(t *testing.T) {
+	weekend := &Window{Name: "weekend", Days: []time.Weekday{time.Saturday, time.Sunday}, Interval: time.Minute}
+	weekday := at("2030-03-04 12:00")
+	saturday := at("2030-03-09 12:00")
+
+	tests := []struct {
+		name      string
+		policy    *Policy
+		failures  int64
+		unchanged int64
+		now       time.Time
+		expected  time.Duration
+	}{
+		{"base interval", &Policy{}, 0, 0, weekday, 10 * time.Second},
+		{"backoff", &Policy{BackoffFactor: 2, MaxInterval: time.Hour}, 3, 0, weekday, 80 * time.Second},
+		{"backoff capped", &Policy{BackoffFactor: 2, MaxInterval: time.Minute}, 10, 0, weekday, time.Minute},
+		{"backoff without a max interval", &Policy{BackoffFactor: 2}, 3, 0, weekday, 80 * time.Second},
+		{"stable slowdown without a max interval", &Policy{StableAfter: 5, StableFactor: 2}, 0, 7, weekday, 40 * time.Second},
+		{"backoff without a factor", &Policy{MaxInterval: time.Hour}, 3, 0, weekday, 10 * time.Second},
+		{"not stable yet", &Policy{StableAfter: 5, StableFactor: 2, MaxInterval: time.Hour}, 0, 5, weekday, 10 * time.Second},
+		{"stable slowdown", &Policy{StableAfter: 5, StableFactor: 2, MaxInterval: time.Hour}, 0, 7, weekday, 40 * time.Second},
+		{"stable slowdown capped", &Policy{StableAfter: 5, StableFactor: 2, MaxInterval: 30 * time.Second}, 0, 50, weekday, 30 * time.Second},
+		{"failures take precedence", &Policy{BackoffFactor: 3, StableAfter: 1, StableFactor: 2, MaxInterval: time.Hour}, 1, 10, weekday, 30 * time.Second},
+		{"window interval", &Policy{Windows: []*Window{weekend}}, 0, 0, saturday, time.Minute},
+		{"window above the max interval", &Policy{Windows: []*Window{weekend}, MaxInterval: 30 * time.Second}, 0, 0, saturday, time.Minute},
+		{"window backoff", &Policy{Windows: []*Window{weekend}, BackoffFactor: 2, MaxInterval: time.Hour}, 2, 0, saturday, 4 * time.Minute},
+		{"outside the window", &Policy{Windows: []*Window{weekend}}, 0, 0, weekday, 10 * time.Second},
+		{"window in the policy's timezone", &Policy{Windows: []*Window{weekend}, Location: time.FixedZone("UTC+13", 13*60*60)}, 0, 0, at("2030-03-08 12:00"), time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &observer.Observer{Interval: 10 * time.Second, Failures: tt.failures, Unchanged: tt.unchanged}
+			if got := tt.policy.NextInterval(o, tt.now); got != tt.expected {
+				t.Errorf("NextInterval() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNextIntervalJitter(t *testing.T) {
+	policy := &Policy{Jitter: 0.1}
+	o := &observer.Observer{Interval: 10 * time.Second}
+
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		interval := policy.NextInterval(o, at("2030-03-04 12:00"))
+		if interval < 9*time.Second || interval > 11*time.Second {
+			t.Fatalf("NextInterval() = %v, want within 10%% of 10s", interval)
+		}
+		seen[interval] = true
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("expected the jitter to vary the intervals")
+	}
+
+	// Never checked more often than every second
+	o.Interval = 100 * time.Millisecond
+	if interval := policy.NextInterval(o, at("2030-03-04 12:00")); interval != time.Second {
+		t.Errorf("NextInterval() = %v, want 1s", interval)
+	}
+}
@@ -0,0 +1,134 @@
+// metrics/metrics.go
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "goptivum"
+
+var (
+	ObserverFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "observer",
+		Name:      "fetch_duration_seconds",
+		Help:      "Time taken to check an observed page, including retries.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"school", "resource"})
+
+	ObserverErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "observer",
+		Name:      "errors_total",
+		Help:      "Number of failed checks of observed pages.",
+	}, []string{"school", "resource"})
+
+	ObserverChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "observer",
+		Name:      "changes_total",
+		Help:      "Number of checks that found an observed page changed.",
+	}, []string{"school", "resource"})
+
+	HubQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "hub",
+		Name:      "queue_depth",
+		Help:      "Number of observers waiting in the task queue of a hub.",
+	}, []string{"school", "resource"})
+
+	HubWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "hub",
+		Name:      "workers",
+		Help:      "Number of workers of a hub.",
+	}, []string{"school", "resource"})
+
+	HubBusyWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "hub",
+		Name:      "busy_workers",
+		Help:      "Number of workers of a hub that are checking an observer.",
+	}, []string{"school", "resource"})
+
+	HubDroppedTasks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "hub",
+		Name:      "dropped_tasks_total",
+		Help:      "Number of due observers dropped because the task queue stayed full.",
+	}, []string{"school", "resource"})
+
+	DatastoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "datastore",
+		Name:      "operation_duration_seconds",
+		Help:      "Time taken by datastore transactions.",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5},
+	}, []string{"operation"})
+
+	SSEClients = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "sse",
+		Name:      "clients",
+		Help:      "Number of clients connected to an SSE hub.",
+	}, []string{"school", "hub"})
+
+	SSEDroppedBroadcasts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sse",
+		Name:      "dropped_broadcasts_total",
+		Help:      "Number of messages an SSE hub failed to deliver.",
+	}, []string{"school", "hub", "reason"})
+
+	SSEEvictedClients = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sse",
+		Name:      "evicted_clients_total",
+		Help:      "Number of clients removed from an SSE hub because they couldn't keep up.",
+	}, []string{"school", "hub"})
+
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPStreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "stream_duration_seconds",
+		Help:      "Time SSE and WebSocket clients stayed connected.",
+		Buckets:   []float64{1, 10, 60, 300, 900, 1800, 3600, 4 * 3600, 12 * 3600},
+	}, []string{"route"})
+
+	WeatherRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "weather",
+		Name:      "requests_total",
+		Help:      "Number of calls to the weather providers.",
+	}, []string{"provider"})
+
+	WeatherFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "weather",
+		Name:      "failures_total",
+		Help:      "Number of failed calls to the weather providers.",
+	}, []string{"provider"})
+)
+
+// Observes the time elapsed since start in the histogram
+func ObserveSince(histogram prometheus.Observer, start time.Time) {
+	histogram.Observe(time.Since(start).Seconds())
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
@@ -18,10 +19,35 @@ type Observer struct {
 	URL            string
 	ExtractContent func(*goquery.Document) string
 	Hash           string
+	ETag           string
+	LastModified   string
+	// Pages whose content is read from other pages (like framesets) are
+	// always fetched in full, a 304 doesn't mean the content is the same
+	Unconditional  bool
 	Interval       time.Duration
-	Callback       func()
+	Callback       func() error
 	NextRun        time.Time
+	Failures       int64
+	Unchanged      int64
+	LastChecked    time.Time
 	Mu 		       sync.RWMutex
+
+	saved State
+}
+
+// State is what's kept of an observer between restarts
+type State struct {
+	Hash         string
+	ETag         string
+	LastModified string
+	LastChecked  time.Time
+}
+
+// StateStore persists observer states, keyed by the observed URL
+type StateStore interface {
+	LoadState(url string) (*State, error)
+	SaveState(url string, state *State) error
+	DeleteState(url string) error
 }
 
 func hashContent(content string) string {
@@ -30,7 +56,7 @@ func hashContent(content string) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-func NewObserver(index int64, url string, interval time.Duration, extractFunc func(*goquery.Document) string, callbackFunc func()) *Observer {
+func NewObserver(index int64, url string, interval time.Duration, extractFunc func(*goquery.Document) string, callbackFunc func() error) *Observer {
 	return &Observer{
 		URL:            url,
 		Index:          index,
@@ -41,39 +67,141 @@ func NewObserver(index int64, url string, interval time.Duration, extractFunc fu
 	}
 }
 
-func (o *Observer) fetchContent(ctx context.Context, client *http.Client) (*goquery.Document, error) {
+// GetState returns a copy of the state kept between restarts
+func (o *Observer) GetState() *State {
+	o.Mu.RLock()
+	defer o.Mu.RUnlock()
+
+	return &State{
+		Hash:         o.Hash,
+		ETag:         o.ETag,
+		LastModified: o.LastModified,
+		LastChecked:  o.LastChecked,
+	}
+}
+
+// SetState restores the state, a nil state resets it
+func (o *Observer) SetState(state *State) {
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
+
+	if state == nil {
+		state = &State{}
+	}
+
+	o.Hash = state.Hash
+	o.ETag = state.ETag
+	o.LastModified = state.LastModified
+	o.LastChecked = state.LastChecked
+	o.Failures = 0
+	o.Unchanged = 0
+	o.saved = *state
+}
+
+// StateToSave returns the state if it differs from the last saved one, a
+// state that only differs by when it was checked is saved at most once per
+// interval. The caller marks it saved once it's been written
+func (o *Observer) StateToSave(interval time.Duration) (*State, bool) {
+	o.Mu.RLock()
+	defer o.Mu.RUnlock()
+
+	state := &State{
+		Hash:         o.Hash,
+		ETag:         o.ETag,
+		LastModified: o.LastModified,
+		LastChecked:  o.LastChecked,
+	}
+
+	changed := state.Hash != o.saved.Hash ||
+		state.ETag != o.saved.ETag ||
+		state.LastModified != o.saved.LastModified
+
+	return state, changed || state.LastChecked.Sub(o.saved.LastChecked) >= interval
+}
+
+// MarkSaved records the state as the last saved one
+func (o *Observer) MarkSaved(state *State) {
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
+	o.saved = *state
+}
+
+// Invalidate forgets the hash and the validators, so the next check
+// fetches the whole page and reports it changed
+func (o *Observer) Invalidate() {
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
+
+	o.Hash = ""
+	o.ETag = ""
+	o.LastModified = ""
+}
+
+// Checked reports whether the observer has been checked at least
+// once, either since it was created or before a restart
+func (o *Observer) Checked() bool {
+	o.Mu.RLock()
+	defer o.Mu.RUnlock()
+	return !o.LastChecked.IsZero()
+}
+
+// Sends a conditional request when the page was already hashed and the server
+// gave validators for it, notModified is set if the server replied with a 304
+func (o *Observer) fetchContent(ctx context.Context, client *http.Client) (doc *goquery.Document, notModified bool, err error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", o.URL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+		return nil, false, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+
+	if o.Hash != "" && !o.Unconditional {
+		if o.ETag != "" {
+			req.Header.Set("If-None-Match", o.ETag)
+		}
+		if o.LastModified != "" {
+			req.Header.Set("If-Modified-Since", o.LastModified)
+		}
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch the page: %v", err)
+		return nil, false, fmt.Errorf("failed to fetch the page: %v", err)
 	}
 	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	doc, err = goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse the HTML: %v", err)
+		return nil, false, fmt.Errorf("failed to parse the HTML: %v", err)
 	}
 
-	return doc, nil
+	// Servers without validators leave these empty, so the page keeps being hashed
+	o.ETag = resp.Header.Get("ETag")
+	o.LastModified = resp.Header.Get("Last-Modified")
+
+	return doc, false, nil
 }
 
 func (o *Observer) compareHash(ctx context.Context, client *http.Client) (bool, error) {
-	o.Mu.RLock()
-	defer o.Mu.RUnlock()
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
 
 	var doc *goquery.Document
+	var notModified bool
 	var err error
 
 	for i := 0; i < 3; i++ {
-		doc, err = o.fetchContent(ctx, client)
+		doc, notModified, err = o.fetchContent(ctx, client)
 		if err == nil {
 			break
 		}
-		fmt.Printf("error fetching content from %s, retrying... (%d/3)\n", o.URL, i+1)
+		slog.Warn("error fetching content, retrying", "index", o.Index, "url", o.URL, "attempt", i+1, "error", err)
 		time.Sleep(1 * time.Second)
 	}
 
@@ -81,10 +209,15 @@ func (o *Observer) compareHash(ctx context.Context, client *http.Client) (bool,
 		return false, fmt.Errorf("error fetching content from %s: %v", o.URL, err)
 	}
 
+	if notModified {
+		slog.Debug("page not modified", "index", o.Index, "url", o.URL)
+		return false, nil
+	}
+
 	content := o.ExtractContent(doc)
 	hash := hashContent(content)
 
-	fmt.Printf("checking hash for %s\n", o.URL)
+	slog.Debug("checking hash", "index", o.Index, "url", o.URL)
 
 	if hash != o.Hash {
 		o.Hash = hash
@@ -96,10 +229,34 @@ func (o *Observer) compareHash(ctx context.Context, client *http.Client) (bool,
 
 // Helper method to integrate with the Hub's worker pool.
 func (o *Observer) CompareHashWithClient(ctx context.Context, client *http.Client) bool {
-	changed, err := o.compareHash(ctx, client)
+	changed, err := o.Check(ctx, client)
 	if err != nil {
-		fmt.Println(err)
+		slog.Error("error checking observer", "index", o.Index, "url", o.URL, "error", err)
 		return false
 	}
 	return changed
+}
+
+// Check compares the hash and keeps count of the consecutive failed and unchanged
+// checks, which the hub uses to adapt the polling interval
+func (o *Observer) Check(ctx context.Context, client *http.Client) (bool, error) {
+	changed, err := o.compareHash(ctx, client)
+
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
+
+	o.LastChecked = time.Now()
+
+	switch {
+	case err != nil:
+		o.Failures++
+	case changed:
+		o.Failures = 0
+		o.Unchanged = 0
+	default:
+		o.Failures = 0
+		o.Unchanged++
+	}
+
+	return changed, err
 }
\ No newline at end of file
@@ -0,0 +1,415 @@
+package scraper
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/hub"
+	"smuggr.xyz/goptivum/core/scraper/optivumtest"
+	"smuggr.xyz/goptivum/core/sse"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+func lessonAt(day int, period int64, lesson *models.Lesson) *models.Schedule {
+	schedule := &models.Schedule{}
+	for i := 0; i < 5; i++ {
+		schedule.ScheduleDays = append(schedule.ScheduleDays, &models.ScheduleDay{})
+	}
+	schedule.ScheduleDays[day].LessonGroups = []*models.LessonGroup{
+		{Period: period, Lessons: []*models.Lesson{lesson}},
+	}
+	return schedule
+}
+
+func newTestSchool() *models.School {
+	school := &models.School{
+		Divisions: []*models.Division{
+			{Index: 1, Designator: "1A", FullName: "technikum", Schedule: lessonAt(0, 1, &models.Lesson{
+				FullName: "matematyka", SubjectName: "matematyka", TeacherIndex: 1, RoomIndex: 1,
+			})},
+			{Index: 2, Designator: "2TI", FullName: "informatyk", Schedule: lessonAt(2, 3, &models.Lesson{
+				FullName: "inf-1/2", SubjectName: "inf", GroupLabel: "1/2", GroupNumber: 1, GroupTotal: 2, TeacherIndex: 2, RoomIndex: 2,
+			})},
+		},
+		Teachers: []*models.Teacher{
+			{Index: 1, Designator: "JK", FullName: "J.Kowalski"},
+			{Index: 2, Designator: "AN", FullName: "A.Nowak"},
+		},
+		Rooms: []*models.Room{
+			{Index: 1, Designator: "101", FullName: "matematyczna"},
+			{Index: 2, Designator: "105", FullName: "informatyczna"},
+		},
+	}
+
+	optivumtest.DeriveSchedules(school)
+
+	return school
+}
+
+// Points a school at the mock server and an in-memory datastore
+func setupScraper(t *testing.T, server *optivumtest.Server) *School {
+	t.Helper()
+
+	scraperConfig := config.ScraperConfig{
+		BaseUrl: server.BaseURL(),
+	}
+	scraperConfig.Endpoints.DivisionsList = optivumtest.ListEndpoint
+	scraperConfig.Endpoints.TeachersList = optivumtest.ListEndpoint
+	scraperConfig.Endpoints.RoomsList = optivumtest.ListEndpoint
+	scraperConfig.Endpoints.Division = optivumtest.DivisionEndpoint
+	scraperConfig.Endpoints.Teacher = optivumtest.TeacherEndpoint
+	scraperConfig.Endpoints.Room = optivumtest.RoomEndpoint
+	scraperConfig.Polling.Interval = time.Second
+
+	school := NewSchool(config.SchoolConfig{
+		ID:        "test",
+		KeyPrefix: "test:",
+		Scraper:   scraperConfig,
+	})
+	school.Client = server.Client()
+
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("error opening datastore: %v", err)
+	}
+	datastore.DB = db
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return school
+}
+
+func TestScrapeIndexes(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+	school := setupScraper(t, server)
+
+	tests := []struct {
+		name   string
+		scrape func() ([]int64, error)
+	}{
+		{"divisions", school.ScrapeDivisionsIndexes},
+		{"teachers", school.ScrapeTeachersIndexes},
+		{"rooms", school.ScrapeRoomsIndexes},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexes, err := test.scrape()
+			if err != nil {
+				t.Fatalf("error scraping indexes: %v", err)
+			}
+			if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 2 {
+				t.Errorf("got indexes %v, want [1 2]", indexes)
+			}
+		})
+	}
+}
+
+func TestScrapePages(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+	school := setupScraper(t, server)
+
+	division, err := school.ScrapeDivision(2)
+	if err != nil {
+		t.Fatalf("error scraping division: %v", err)
+	}
+	if division.Designator != "2TI" || division.FullName != "informatyk" {
+		t.Errorf("division title: got %q %q", division.Designator, division.FullName)
+	}
+
+	lessons := division.Schedule.ScheduleDays[2].LessonGroups
+	if len(lessons) != 1 || lessons[0].Period != 3 {
+		t.Fatalf("division lessons: got %v", lessons)
+	}
+	lesson := lessons[0].Lessons[0]
+	if lesson.SubjectName != "inf" || lesson.GroupLabel != "1/2" || lesson.TeacherIndex != 2 || lesson.RoomIndex != 2 {
+		t.Errorf("division lesson: got %+v", lesson)
+	}
+
+	teacher, err := school.ScrapeTeacher(2)
+	if err != nil {
+		t.Fatalf("error scraping teacher: %v", err)
+	}
+	if teacher.Designator != "AN" || teacher.FullName != "A.Nowak" {
+		t.Errorf("teacher title: got %q %q", teacher.Designator, teacher.FullName)
+	}
+	lesson = teacher.Schedule.ScheduleDays[2].LessonGroups[0].Lessons[0]
+	if lesson.DivisionIndex != 2 || lesson.GroupLabel != "1/2" || lesson.SubjectName != "inf" {
+		t.Errorf("teacher lesson: got %+v", lesson)
+	}
+
+	room, err := school.ScrapeRoom(1)
+	if err != nil {
+		t.Fatalf("error scraping room: %v", err)
+	}
+	if room.Designator != "101" {
+		t.Errorf("room designator: got %q", room.Designator)
+	}
+	lesson = room.Schedule.ScheduleDays[0].LessonGroups[0].Lessons[0]
+	if lesson.DivisionIndex != 1 || lesson.TeacherIndex != 1 || lesson.SubjectName != "matematyka" {
+		t.Errorf("room lesson: got %+v", lesson)
+	}
+}
+
+// Reads the data lines of the server-sent events into the channel
+func readEvents(t *testing.T, url string) <-chan string {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("error connecting to events: %v", err)
+	}
+
+	events := make(chan string, 10)
+	go func() {
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+
+	return events
+}
+
+func waitFor[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+
+	var zero T
+	return zero
+}
+
+func TestObserverRefresh(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+	school := setupScraper(t, server)
+
+	eventsHub := sse.NewHub(10, nil, nil)
+	go eventsHub.Run()
+
+	eventsServer := httptest.NewServer(eventsHub.Handler())
+	defer func() {
+		// The events stream never ends on its own
+		eventsServer.CloseClientConnections()
+		eventsServer.Close()
+	}()
+
+	events := readEvents(t, eventsServer.URL)
+	for eventsHub.GetConnectedClients() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	refreshChan := make(chan RefreshEvent)
+	refreshed := make(chan RefreshEvent)
+	go func() {
+		for event := range refreshChan {
+			eventsHub.BroadcastEvent(event.Name(), event.Index)
+			refreshed <- event
+		}
+	}()
+
+	divisionsHub := hub.NewHub(1, false, nil, datastoreStateStore{store: school.Store})
+	divisionsHub.Start()
+	defer divisionsHub.Stop()
+
+	divisionsHub.AddObserver(school.newDivisionObserver(1, &refreshChan))
+
+	if event := waitFor(t, refreshed, "first refresh"); event.Index != 1 || event.Name() != "division.updated" {
+		t.Fatalf("refresh: got %s of %d, want division.updated of 1", event.Name(), event.Index)
+	}
+	if event := waitFor(t, events, "first event"); event != "1" {
+		t.Errorf("event: got %q, want \"1\"", event)
+	}
+
+	division, err := school.Store.GetDivision(1)
+	if err != nil {
+		t.Fatalf("error reading division: %v", err)
+	}
+	if subject := division.Schedule.ScheduleDays[0].LessonGroups[0].Lessons[0].SubjectName; subject != "matematyka" {
+		t.Errorf("stored subject: got %q, want \"matematyka\"", subject)
+	}
+
+	server.Update(func(school *models.School) {
+		lesson := school.Divisions[0].Schedule.ScheduleDays[0].LessonGroups[0].Lessons[0]
+		lesson.FullName = "fizyka"
+		lesson.SubjectName = "fizyka"
+	})
+
+	waitFor(t, refreshed, "refresh after the change")
+	waitFor(t, events, "event after the change")
+
+	division, err = school.Store.GetDivision(1)
+	if err != nil {
+		t.Fatalf("error reading division: %v", err)
+	}
+	if subject := division.Schedule.ScheduleDays[0].LessonGroups[0].Lessons[0].SubjectName; subject != "fizyka" {
+		t.Errorf("stored subject: got %q, want \"fizyka\"", subject)
+	}
+
+	history, err := school.Store.GetHistory(DivisionResource.String(), 1)
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if len(history.Versions) != 2 {
+		t.Errorf("history: got %d version(s), want 2", len(history.Versions))
+	}
+}
+
+func TestStartWithUpstreamDown(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+	school := setupScraper(t, server)
+
+	discoveryRetryDelay = 10 * time.Millisecond
+	defer func() {
+		discoveryRetryDelay = 5 * time.Second
+	}()
+
+	if err := school.Store.SetDivision(&models.Division{Index: 1, Designator: "1A", FullName: "technikum"}); err != nil {
+		t.Fatalf("error storing division: %v", err)
+	}
+
+	for _, resource := range []*ScraperResource{school.Divisions, school.Teachers, school.Rooms} {
+		go func() {
+			for range resource.RefreshChan {
+			}
+		}()
+	}
+
+	school.Config.Quantities.Workers.Division = 1
+	school.Config.Quantities.Workers.Teacher = 1
+	school.Config.Quantities.Workers.Room = 1
+
+	server.SetDown(true)
+
+	started := time.Now()
+	if err := school.Start(); err != nil {
+		t.Fatalf("error starting school: %v", err)
+	}
+	defer school.Cleanup()
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("start took %s with the site down", elapsed)
+	}
+	if school.Ready() {
+		t.Errorf("school is ready with the site down")
+	}
+	if indexes := school.Divisions.GetIndexes(); len(indexes) != 1 || indexes[0] != 1 {
+		t.Errorf("restored indexes: got %v, want [1]", indexes)
+	}
+	if designator := school.Divisions.GetDesignatorFromIndex(1); designator != "1A" {
+		t.Errorf("restored designator: got %q, want \"1A\"", designator)
+	}
+
+	server.SetDown(false)
+
+	ready := make(chan bool)
+	go func() {
+		for !school.Ready() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		ready <- true
+	}()
+	waitFor(t, ready, "the school to be ready")
+
+	if indexes := school.Rooms.GetIndexes(); len(indexes) != 2 {
+		t.Errorf("discovered rooms: got %v, want [1 2]", indexes)
+	}
+}
+
+func TestListObserversPerLayout(t *testing.T) {
+	tests := []struct {
+		layout     IndexLayout
+		listLayout optivumtest.ListLayout
+	}{
+		{DropdownLayout, optivumtest.DropdownList},
+		{TilesLayout, optivumtest.TilesList},
+		{FramesLayout, optivumtest.FramesList},
+		{AutoLayout, optivumtest.FramesList},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout.String()+" "+string(test.listLayout), func(t *testing.T) {
+			server := optivumtest.NewServer(newTestSchool())
+			defer server.Close()
+			server.SetListLayout(test.listLayout)
+
+			school := setupScraper(t, server)
+			school.Config.IndexLayout = test.layout.String()
+
+			refreshChan := make(chan RefreshEvent)
+			listChanged := make(chan ResourceType, 10)
+			go func() {
+				for event := range refreshChan {
+					if event.Type == ListChangedRefresh {
+						listChanged <- event.Resource
+					}
+				}
+			}()
+
+			store := datastoreStateStore{store: school.Store}
+			for _, resource := range []*ScraperResource{school.Divisions, school.Teachers, school.Rooms} {
+				resource.Hub = hub.NewHub(1, false, nil, store)
+				defer resource.StopHub()
+
+				// The pages of the discovered items refresh on their own channel
+				go func() {
+					for range resource.RefreshChan {
+					}
+				}()
+			}
+
+			school.ObserveDivisions(&refreshChan)
+			school.ObserveTeachers(&refreshChan)
+			school.ObserveRooms(&refreshChan)
+
+			// Waits for a list change of every resource
+			waitForLists := func(what string) {
+				t.Helper()
+
+				changed := make(map[ResourceType]bool)
+				for len(changed) < 3 {
+					changed[waitFor(t, listChanged, what)] = true
+				}
+			}
+
+			waitForLists("the first list check")
+			if indexes := school.Divisions.GetIndexes(); len(indexes) != 2 {
+				t.Fatalf("divisions: got %v, want [1 2]", indexes)
+			}
+
+			server.Update(func(school *models.School) {
+				school.Divisions = append(school.Divisions, &models.Division{Index: 3, Designator: "3B", Schedule: lessonAt(1, 1, &models.Lesson{FullName: "wf"})})
+				school.Teachers = append(school.Teachers, &models.Teacher{Index: 3, Designator: "PW", FullName: "P.Wiśniewski", Schedule: lessonAt(1, 1, &models.Lesson{FullName: "wf"})})
+				school.Rooms = append(school.Rooms, &models.Room{Index: 3, Designator: "204", Schedule: lessonAt(1, 1, &models.Lesson{FullName: "wf"})})
+			})
+
+			waitForLists("the list change")
+			for _, resource := range []*ScraperResource{school.Divisions, school.Teachers, school.Rooms} {
+				if indexes := resource.GetIndexes(); len(indexes) != 3 {
+					t.Errorf("%s: got %v, want [1 2 3]", resource.Type, indexes)
+				}
+			}
+		})
+	}
+}
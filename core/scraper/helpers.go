@@ -2,78 +2,67 @@ package scraper
 
 import (
 	"fmt"
+	"log/slog"
+	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"smuggr.xyz/goptivum/common/models"
 	"smuggr.xyz/goptivum/common/utils"
-	"smuggr.xyz/goptivum/core/datastore"
 	"smuggr.xyz/goptivum/core/observer"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"google.golang.org/protobuf/proto"
 )
 
-func makeDivisionEndpoint(index int64) string {
-	return fmt.Sprintf(Config.Endpoints.Division, index)
+func (s *School) makeDivisionEndpoint(index int64) string {
+	return fmt.Sprintf(s.Config.Endpoints.Division, index)
 }
 
-func makeTeacherEndpoint(index int64) string {
-	return fmt.Sprintf(Config.Endpoints.Teacher, index)
+func (s *School) makeTeacherEndpoint(index int64) string {
+	return fmt.Sprintf(s.Config.Endpoints.Teacher, index)
 }
 
-func makeRoomEndpoint(index int64) string {
-	return fmt.Sprintf(Config.Endpoints.Room, index)
+func (s *School) makeRoomEndpoint(index int64) string {
+	return fmt.Sprintf(s.Config.Endpoints.Room, index)
 }
 
-func waitForFirstRefresh() {
-	var wg sync.WaitGroup
-
-	divisionObservers := len(DivisionsScraperResource.Hub.GetAllObservers(true))
-	teacherObservers := len(TeachersScraperResource.Hub.GetAllObservers(true))
-	roomObservers := len(RoomsScraperResource.Hub.GetAllObservers(true))
-
-	totalObservers := divisionObservers + teacherObservers + roomObservers
-
-	if totalObservers > 0 {
-		wg.Add(totalObservers)
-	} else {
-		fmt.Println("no observers to wait for")
-		return
-	}
-
-	waitForRefresh := func(ch <-chan int64, count int) {
-		fmt.Println("waiting for refresh:", count)
-		for i := 0; i < count; i++ {
-			go func() {
-				<-ch
-				select {
-				case <-ch:
-					wg.Done()
-				case <-time.After(20 * time.Second):
-					fmt.Println("timed out waiting for refresh")
-					wg.Done()
-				}
-			}()
+func countUncheckedObservers(resource *ScraperResource) int {
+	count := 0
+	for index, o := range resource.Hub.GetAllObservers(true) {
+		if index != 0 && !o.Checked() {
+			count++
 		}
 	}
+	return count
+}
 
-	waitForRefresh(DivisionsScraperResource.RefreshChan, divisionObservers)
-	waitForRefresh(TeachersScraperResource.RefreshChan, teacherObservers)
-	waitForRefresh(RoomsScraperResource.RefreshChan, roomObservers)
+// Waits until every observer has been checked once, observers restored
+// from the datastore count as checked and only refresh if their page changed
+func (s *School) waitForFirstRefresh() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
 
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+	// Some observers might not be checked in time (e.g. the site
+	// is slow) so we only wait for a certain amount of time
+	timeout := time.After(15 * time.Second)
 
-	// Some observers might not refresh so we
-	// need to wait for a certain amount of time
-	select {
-	case <-done:
-	case <-time.After(15 * time.Second):
+	for {
+		unchecked := countUncheckedObservers(s.Divisions) + countUncheckedObservers(s.Teachers) + countUncheckedObservers(s.Rooms)
+		if unchecked == 0 {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			s.logger().Warn("timed out waiting for observers", "unchecked", unchecked)
+			return
+		case <-s.quit:
+			return
+		}
 	}
 }
 
@@ -147,7 +136,94 @@ func parseTimeRange(s string) (models.TimeRange, error) {
 	return models.TimeRange{Start: &start, End: &end}, nil
 }
 
-func parseLesson(rowElement *goquery.Selection, timeRange *models.TimeRange) ([]*models.Lesson, error) {
+// Resolves the index of a resource referenced in a lesson cell, the link
+// (e.g. n12.html) takes precedence, otherwise the designator is looked up
+// in the resource's metadata. Returns 0 if the index can't be resolved.
+func parseResourceIndex(element *goquery.Selection, indexRegex *regexp.Regexp, resource *ScraperResource) int64 {
+	if element.Length() == 0 {
+		return 0
+	}
+
+	if href, exists := element.Attr("href"); exists {
+		match := indexRegex.FindStringSubmatch(href)
+		if len(match) > 1 {
+			index, err := strconv.ParseInt(match[1], 10, 64)
+			if err == nil {
+				return index
+			}
+			slog.Warn("error converting index from href", "href", href, "error", err)
+		}
+	}
+
+	designator := strings.TrimSpace(element.Text())
+	if designator == "" || resource == nil {
+		return 0
+	}
+
+	duplicates := resource.GetIndexFromDesignator(designator)
+	if duplicates == nil || len(duplicates.Values) == 0 {
+		return 0
+	}
+
+	return duplicates.Values[0]
+}
+
+// Matches the group suffixes Optivum appends to lesson and division names,
+// e.g. "j.ang-2/2", "inf-1/3", "wf-gr1", "wf-Gr.2" or "2TI-1/2"
+var lessonGroupRegex = regexp.MustCompile(`(?i)^(.*?)\s*-\s*((?:gr\.?\s*)?(\d+)(?:\s*/\s*(\d+))?)$`)
+
+type lessonGroup struct {
+	Label  string
+	Number int64
+	Total  int64
+}
+
+// Splits a raw name like "j.ang-2/2" into its base name and the group,
+// the group is nil if the name has no recognizable group suffix.
+func splitLessonGroup(s string) (string, *lessonGroup) {
+	s = strings.TrimSpace(s)
+	match := lessonGroupRegex.FindStringSubmatch(s)
+	if len(match) < 5 || match[1] == "" {
+		return s, nil
+	}
+
+	group := &lessonGroup{
+		Label: strings.ReplaceAll(match[2], " ", ""),
+	}
+	group.Number, _ = strconv.ParseInt(match[3], 10, 64)
+	if match[4] != "" {
+		group.Total, _ = strconv.ParseInt(match[4], 10, 64)
+	}
+
+	return strings.TrimSpace(match[1]), group
+}
+
+// On teacher and room pages the group is written right after the division
+// link (<a class="o">2TI</a>-1/2), so it has to be read from the text node
+// that follows the element.
+func parseTrailingGroup(element *goquery.Selection) *lessonGroup {
+	if element.Length() == 0 {
+		return nil
+	}
+
+	next := element.Nodes[0].NextSibling
+	if next == nil || next.Type != html.TextNode {
+		return nil
+	}
+
+	suffix := strings.TrimSpace(strings.ReplaceAll(next.Data, "\u00a0", " "))
+	if !strings.HasPrefix(suffix, "-") {
+		return nil
+	}
+	suffix = strings.FieldsFunc(suffix, func(r rune) bool {
+		return r == ',' || r == ' '
+	})[0]
+
+	_, group := splitLessonGroup(strings.TrimSpace(element.Text()) + suffix)
+	return group
+}
+
+func (s *School) parseLesson(rowElement *goquery.Selection, timeRange *models.TimeRange) ([]*models.Lesson, error) {
 	var lessons []*models.Lesson
 
 	html, err := rowElement.Html()
@@ -168,7 +244,7 @@ func parseLesson(rowElement *goquery.Selection, timeRange *models.TimeRange) ([]
 
 		segmentDoc, err := goquery.NewDocumentFromReader(strings.NewReader(segmentHTML))
 		if err != nil {
-			fmt.Println("error parsing segment:", err)
+			slog.Warn("error parsing segment", "error", err)
 			continue
 		}
 
@@ -176,16 +252,31 @@ func parseLesson(rowElement *goquery.Selection, timeRange *models.TimeRange) ([]
 		if lessonName == "" {
 			lessonName = strings.TrimSpace(segmentDoc.Text())
 		}
-		teacher := strings.TrimSpace(segmentDoc.Find("a.n").First().Text())
-		division := strings.TrimSpace(segmentDoc.Find("a.o").First().Text())
-		room := strings.TrimSpace(segmentDoc.Find("a.s").First().Text())
+		teacherElement := segmentDoc.Find(".n").First()
+		divisionElement := segmentDoc.Find(".o").First()
+		roomElement := segmentDoc.Find(".s").First()
+
+		subjectName, group := splitLessonGroup(lessonName)
+		if group == nil {
+			group = parseTrailingGroup(divisionElement)
+		}
 
 		lesson := &models.Lesson{
 			TimeRange:          timeRange,
 			FullName:           lessonName,
-			TeacherDesignator:  teacher,
-			DivisionDesignator: division,
-			RoomDesignator:     room,
+			SubjectName:        subjectName,
+			TeacherDesignator:  strings.TrimSpace(teacherElement.Text()),
+			TeacherIndex:       parseResourceIndex(teacherElement, TeacherIndexRegex, s.Teachers),
+			DivisionDesignator: strings.TrimSpace(divisionElement.Text()),
+			DivisionIndex:      parseResourceIndex(divisionElement, DivisionIndexRegex, s.Divisions),
+			RoomDesignator:     strings.TrimSpace(roomElement.Text()),
+			RoomIndex:          parseResourceIndex(roomElement, RoomIndexRegex, s.Rooms),
+		}
+
+		if group != nil {
+			lesson.GroupLabel = group.Label
+			lesson.GroupNumber = group.Number
+			lesson.GroupTotal = group.Total
 		}
 		lessons = append(lessons, lesson)
 	}
@@ -248,9 +339,67 @@ func scrapeRoomTitle(doc *goquery.Document) (string, string, error) {
 	return designator, fullName, nil
 }
 
-func scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
+func parsePeriodNumber(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\u00a0", " "))
+	number, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid period number: %s %v", s, err)
+	}
+
+	return number, nil
+}
+
+// Scrapes the bell schedule from the "nr" and "g" columns of the schedule table,
+// it's the same on every schedule page of the school.
+func scrapePeriods(doc *goquery.Document) (*models.Periods, error) {
+	periods := &models.Periods{}
+
+	doc.Find("table.tabela > tbody > tr").Each(func(i int, rowElement *goquery.Selection) {
+		numberElement := rowElement.Find("td.nr").First()
+		timeRangeElement := rowElement.Find("td.g").First()
+		if numberElement.Length() == 0 || timeRangeElement.Length() == 0 {
+			return
+		}
+
+		number, err := parsePeriodNumber(numberElement.Text())
+		if err != nil {
+			slog.Warn("error parsing period number", "error", err)
+			return
+		}
+
+		timeRange, err := parseTimeRange(timeRangeElement.Text())
+		if err != nil {
+			slog.Warn("error parsing time range", "error", err)
+			return
+		}
+
+		periods.Periods = append(periods.Periods, &models.Period{
+			Number:    number,
+			TimeRange: &timeRange,
+		})
+	})
+
+	if len(periods.Periods) == 0 {
+		return nil, fmt.Errorf("no periods found")
+	}
+
+	return periods, nil
+}
+
+// Saves the bell schedule if it differs from the stored one
+func (s *School) updatePeriods(periods *models.Periods) error {
+	storedPeriods, err := s.Store.GetPeriods()
+	if err == nil && proto.Equal(storedPeriods, periods) {
+		return nil
+	}
+
+	return s.Store.SetPeriods(periods)
+}
+
+func (s *School) scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
 	var schedule *models.Schedule
 	var timeRange *models.TimeRange
+	var period int64
 
 	rowsSelection := doc.Find("table.tabela > tbody > tr")
 	firstRow := rowsSelection.First()
@@ -271,6 +420,7 @@ func scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
 
 	schedule = &models.Schedule{
 		ScheduleDays: make([]*models.ScheduleDay, workDays),
+		HasPeriods:   true,
 	}
 
 	for i := 0; i < workDays; i++ {
@@ -286,12 +436,20 @@ func scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
 			columnNumber++
 		}
 
-		// first column is row count
+		// first column is row count (period number)
 		// second column is time range
-		if columnNumber == 2 {
+		if columnNumber == 1 {
+			_period, err := parsePeriodNumber(rowElement.Text())
+			if err != nil {
+				slog.Warn("error parsing period number", "error", err)
+				period = 0
+				return
+			}
+			period = _period
+		} else if columnNumber == 2 {
 			_timerange, err := parseTimeRange(rowElement.Text())
 			if err != nil {
-				fmt.Println("error parsing time range", err)
+				slog.Warn("error parsing time range", "error", err)
 				return
 			}
 			timeRange = &_timerange
@@ -306,15 +464,16 @@ func scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
 			if utils.IsEmptyOrInvisible(rowElement.Text()) {
 				return
 			}
-			lessons, err := parseLesson(rowElement, timeRange)
+			lessons, err := s.parseLesson(rowElement, timeRange)
 			if err != nil {
-				fmt.Println("error parsing lessons", err)
+				slog.Warn("error parsing lessons", "error", err)
 				return
 			}
 			//fmt.Println("dayOfWeek:", dayOfWeek, "lessons:", lessons, workDays)
 
 			lessonGroup := &models.LessonGroup{
 				Lessons: lessons,
+				Period:  period,
 			}
 
 			schedule.ScheduleDays[dayOfWeek-1].LessonGroups = append(
@@ -327,7 +486,7 @@ func scrapeSchedule(doc *goquery.Document) (*models.Schedule, error) {
 	return schedule, nil
 }
 
-func newDivisionObserver(index int64, refreshChan *chan int64) *observer.Observer {
+func (s *School) newDivisionObserver(index int64, refreshChan *chan RefreshEvent) *observer.Observer {
 	extractFunc := func(doc *goquery.Document) string {
 		var content []string
 		doc.Find("table.tabela").Each(func(i int, table *goquery.Selection) {
@@ -349,28 +508,30 @@ func newDivisionObserver(index int64, refreshChan *chan int64) *observer.Observe
 		return strings.Join(content, " ")
 	}
 
-	callbackFunc := func() {
-		division, err := ScrapeDivision(index)
+	callbackFunc := func() error {
+		division, err := s.ScrapeDivision(index)
 		if err != nil {
-			fmt.Printf("error scraping division: %v\n", err)
-			return
+			return fmt.Errorf("error scraping division: %w", err)
 		}
 
-		*refreshChan <- index
-
-		if err := datastore.SetDivision(division); err != nil {
-			fmt.Printf("error saving division: %v\n", err)
-			return
+		if err := s.Store.SetDivision(division); err != nil {
+			return fmt.Errorf("error saving division: %w", err)
 		}
+
+		s.Divisions.MarkScraped()
+
+		// Notify only after saving so listeners read the new data
+		*refreshChan <- RefreshEvent{Type: UpdatedRefresh, Resource: DivisionResource, Index: index}
+		return nil
 	}
 	
-	url := fmt.Sprintf(Config.BaseUrl+Config.Endpoints.Division, index)
-	interval := time.Duration((index+1)/10+5) * time.Second
+	url := fmt.Sprintf(s.Config.BaseUrl+s.Config.Endpoints.Division, index)
+	interval := s.observerInterval(index)
 
 	return observer.NewObserver(index, url, interval, extractFunc, callbackFunc)
 }
 
-func newTeacherObserver(index int64, refreshChan *chan int64) *observer.Observer {
+func (s *School) newTeacherObserver(index int64, refreshChan *chan RefreshEvent) *observer.Observer {
 	extractFunc := func(doc *goquery.Document) string {
 		var content []string
 		doc.Find("table").Each(func(i int, table *goquery.Selection) {
@@ -386,28 +547,30 @@ func newTeacherObserver(index int64, refreshChan *chan int64) *observer.Observer
 		return strings.Join(content, " ")
 	}
 
-	callbackFunc := func() {
-		teacher, err := ScrapeTeacher(index)
+	callbackFunc := func() error {
+		teacher, err := s.ScrapeTeacher(index)
 		if err != nil {
-			fmt.Printf("error scraping teacher: %v\n", err)
-			return
+			return fmt.Errorf("error scraping teacher: %w", err)
 		}
 
-		*refreshChan <- index
-
-		if err := datastore.SetTeacher(teacher); err != nil {
-			fmt.Printf("error saving teacher: %v\n", err)
-			return
+		if err := s.Store.SetTeacher(teacher); err != nil {
+			return fmt.Errorf("error saving teacher: %w", err)
 		}
+
+		s.Teachers.MarkScraped()
+
+		// Notify only after saving so listeners read the new data
+		*refreshChan <- RefreshEvent{Type: UpdatedRefresh, Resource: TeacherResource, Index: index}
+		return nil
 	}
 
-	url := fmt.Sprintf(Config.BaseUrl+Config.Endpoints.Teacher, index)
-	interval := time.Duration((index+1)/10+5) * time.Second
+	url := fmt.Sprintf(s.Config.BaseUrl+s.Config.Endpoints.Teacher, index)
+	interval := s.observerInterval(index)
 
 	return observer.NewObserver(index, url, interval, extractFunc, callbackFunc)
 }
 
-func newRoomObserver(index int64, refreshChan *chan int64) *observer.Observer {
+func (s *School) newRoomObserver(index int64, refreshChan *chan RefreshEvent) *observer.Observer {
 	extractFunc := func(doc *goquery.Document) string {
 		var content []string
 
@@ -425,23 +588,25 @@ func newRoomObserver(index int64, refreshChan *chan int64) *observer.Observer {
 		return strings.Join(content, " ")
 	}
 
-	callbackFunc := func() {
-		room, err := ScrapeRoom(index)
+	callbackFunc := func() error {
+		room, err := s.ScrapeRoom(index)
 		if err != nil {
-			fmt.Printf("error scraping room: %v\n", err)
-			return
+			return fmt.Errorf("error scraping room: %w", err)
 		}
 
-		*refreshChan <- index
-
-		if err := datastore.SetRoom(room); err != nil {
-			fmt.Printf("error saving room: %v\n", err)
-			return
+		if err := s.Store.SetRoom(room); err != nil {
+			return fmt.Errorf("error saving room: %w", err)
 		}
+
+		s.Rooms.MarkScraped()
+
+		// Notify only after saving so listeners read the new data
+		*refreshChan <- RefreshEvent{Type: UpdatedRefresh, Resource: RoomResource, Index: index}
+		return nil
 	}
 
-	url := fmt.Sprintf(Config.BaseUrl+Config.Endpoints.Room, index)
-	interval := time.Duration((index+1)/10+5) * time.Second
+	url := fmt.Sprintf(s.Config.BaseUrl+s.Config.Endpoints.Room, index)
+	interval := s.observerInterval(index)
 
 	return observer.NewObserver(index, url, interval, extractFunc, callbackFunc)
 }
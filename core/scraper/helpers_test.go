@@ -0,0 +1,280 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func parseCell(t *testing.T, cell string) []*models.Lesson {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(
+		`<table class="tabela"><tbody><tr>` + cell + `</tr></tbody></table>`,
+	))
+	if err != nil {
+		t.Fatalf("error parsing cell: %v", err)
+	}
+
+	lessons, err := new(School).parseLesson(doc.Find("td.l").First(), nil)
+	if err != nil {
+		t.Fatalf("error parsing lesson: %v", err)
+	}
+
+	return lessons
+}
+
+func TestSplitLessonGroup(t *testing.T) {
+	tests := []struct {
+		input   string
+		subject string
+		label   string
+		number  int64
+		total   int64
+	}{
+		{"matematyka", "matematyka", "", 0, 0},
+		{"j.ang-2/2", "j.ang", "2/2", 2, 2},
+		{"inf-1/3", "inf", "1/3", 1, 3},
+		{"wf-gr1", "wf", "gr1", 1, 0},
+		{"wf-Gr.2", "wf", "Gr.2", 2, 0},
+		{"j.niem - 1/2", "j.niem", "1/2", 1, 2},
+		{"2TI-1/2", "2TI", "1/2", 1, 2},
+		{"wf-dz", "wf-dz", "", 0, 0},
+		{"-1/2", "-1/2", "", 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			subject, group := splitLessonGroup(test.input)
+			if subject != test.subject {
+				t.Errorf("subject: got %q, want %q", subject, test.subject)
+			}
+
+			if test.label == "" {
+				if group != nil {
+					t.Errorf("group: got %+v, want nil", group)
+				}
+				return
+			}
+
+			if group == nil {
+				t.Fatalf("group: got nil, want %q", test.label)
+			}
+			if group.Label != test.label || group.Number != test.number || group.Total != test.total {
+				t.Errorf("group: got %+v, want {%s %d %d}", group, test.label, test.number, test.total)
+			}
+		})
+	}
+}
+
+func TestParsePeriodNumber(t *testing.T) {
+	tests := []struct {
+		input  string
+		number int64
+		ok     bool
+	}{
+		{"0", 0, true},
+		{"1", 1, true},
+		{" 12 ", 12, true},
+		{"\u00a07\u00a0", 7, true},
+		{"", 0, false},
+		{"1.", 0, false},
+		{"nr", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			number, err := parsePeriodNumber(test.input)
+			if (err == nil) != test.ok {
+				t.Fatalf("error: got %v, want ok %v", err, test.ok)
+			}
+			if number != test.number {
+				t.Errorf("number: got %d, want %d", number, test.number)
+			}
+		})
+	}
+}
+
+func periodsDocument(t *testing.T, rows string) *goquery.Document {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(
+		`<table class="tabela"><tbody><tr><th>Nr</th><th>Godz</th></tr>` + rows + `</tbody></table>`,
+	))
+	if err != nil {
+		t.Fatalf("error parsing periods: %v", err)
+	}
+
+	return doc
+}
+
+func TestScrapePeriods(t *testing.T) {
+	type period struct {
+		number                                     int64
+		startHour, startMinute, endHour, endMinute int64
+	}
+
+	tests := []struct {
+		name    string
+		rows    string
+		periods []period
+	}{
+		{
+			"period 0",
+			`<tr><td class="nr">0</td><td class="g">7:10- 7:55</td></tr>` +
+				`<tr><td class="nr">1</td><td class="g">8:00- 8:45</td></tr>`,
+			[]period{{0, 7, 10, 7, 55}, {1, 8, 0, 8, 45}},
+		},
+		{
+			"padded ranges",
+			`<tr><td class="nr">9</td><td class="g"> 15:30 - 16:15 </td></tr>`,
+			[]period{{9, 15, 30, 16, 15}},
+		},
+		{
+			"missing columns",
+			`<tr><td class="nr">1</td><td class="l">matematyka</td></tr>` +
+				`<tr><td class="g">8:50- 9:35</td></tr>` +
+				`<tr><td class="nr">3</td><td class="g">9:45-10:30</td></tr>`,
+			[]period{{3, 9, 45, 10, 30}},
+		},
+		{
+			"invalid rows",
+			`<tr><td class="nr">x</td><td class="g">8:00- 8:45</td></tr>` +
+				`<tr><td class="nr">2</td><td class="g">8:50</td></tr>` +
+				`<tr><td class="nr">3</td><td class="g">9:45-10:30</td></tr>`,
+			[]period{{3, 9, 45, 10, 30}},
+		},
+		{
+			"no periods",
+			`<tr><td class="l">matematyka</td></tr>`,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			periods, err := scrapePeriods(periodsDocument(t, test.rows))
+			if test.periods == nil {
+				if err == nil {
+					t.Errorf("got %v, want an error", periods)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error scraping periods: %v", err)
+			}
+
+			if len(periods.Periods) != len(test.periods) {
+				t.Fatalf("periods: got %d, want %d", len(periods.Periods), len(test.periods))
+			}
+			for i, want := range test.periods {
+				got := periods.Periods[i]
+				timeRange := got.TimeRange
+				if got.Number != want.number ||
+					timeRange.Start.Hour != want.startHour || timeRange.Start.Minute != want.startMinute ||
+					timeRange.End.Hour != want.endHour || timeRange.End.Minute != want.endMinute {
+					t.Errorf("period %d: got %d %d:%02d-%d:%02d, want %d %d:%02d-%d:%02d", i,
+						got.Number, timeRange.Start.Hour, timeRange.Start.Minute, timeRange.End.Hour, timeRange.End.Minute,
+						want.number, want.startHour, want.startMinute, want.endHour, want.endMinute)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLesson(t *testing.T) {
+	type want struct {
+		fullName      string
+		subjectName   string
+		groupLabel    string
+		groupNumber   int64
+		groupTotal    int64
+		teacher       string
+		teacherIndex  int64
+		room          string
+		roomIndex     int64
+		division      string
+		divisionIndex int64
+	}
+
+	tests := []struct {
+		name string
+		cell string
+		want []want
+	}{
+		{
+			name: "division whole class",
+			cell: `<td class="l"><span class="p">matematyka</span> <a href="n3.html" class="n">JK</a> <a href="s1.html" class="s">101</a></td>`,
+			want: []want{
+				{fullName: "matematyka", subjectName: "matematyka", teacher: "JK", teacherIndex: 3, room: "101", roomIndex: 1},
+			},
+		},
+		{
+			name: "division split into groups",
+			cell: `<td class="l"><span style="font-size:85%"><span class="p">j.ang-1/2</span> <a href="n12.html" class="n">AK</a> <a href="s5.html" class="s">21</a></span><br><span style="font-size:85%"><span class="p">j.ang-2/2</span> <a href="n7.html" class="n">BW</a> <a href="s9.html" class="s">23</a></span></td>`,
+			want: []want{
+				{fullName: "j.ang-1/2", subjectName: "j.ang", groupLabel: "1/2", groupNumber: 1, groupTotal: 2, teacher: "AK", teacherIndex: 12, room: "21", roomIndex: 5},
+				{fullName: "j.ang-2/2", subjectName: "j.ang", groupLabel: "2/2", groupNumber: 2, groupTotal: 2, teacher: "BW", teacherIndex: 7, room: "23", roomIndex: 9},
+			},
+		},
+		{
+			name: "division with numbered group",
+			cell: `<td class="l"><span style="font-size:85%"><span class="p">wf-gr1</span> <a href="n4.html" class="n">MN</a> <a href="s20.html" class="s">sg</a></span></td>`,
+			want: []want{
+				{fullName: "wf-gr1", subjectName: "wf", groupLabel: "gr1", groupNumber: 1, teacher: "MN", teacherIndex: 4, room: "sg", roomIndex: 20},
+			},
+		},
+		{
+			name: "teacher page group after division",
+			cell: `<td class="l"><a href="o5.html" class="o">2TI</a>-1/2 <span class="p">inf</span> <a href="s14.html" class="s">105</a></td>`,
+			want: []want{
+				{fullName: "inf", subjectName: "inf", groupLabel: "1/2", groupNumber: 1, groupTotal: 2, division: "2TI", divisionIndex: 5, room: "105", roomIndex: 14},
+			},
+		},
+		{
+			name: "room page group after division",
+			cell: `<td class="l"><a href="n3.html" class="n">JK</a> <a href="o2.html" class="o">1A</a>-gr2 <span class="p">wf</span></td>`,
+			want: []want{
+				{fullName: "wf", subjectName: "wf", groupLabel: "gr2", groupNumber: 2, teacher: "JK", teacherIndex: 3, division: "1A", divisionIndex: 2},
+			},
+		},
+		{
+			name: "room without page",
+			cell: `<td class="l"><span class="p">religia</span> <a href="n8.html" class="n">KS</a> <span class="s">kap</span></td>`,
+			want: []want{
+				{fullName: "religia", subjectName: "religia", teacher: "KS", teacherIndex: 8, room: "kap"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lessons := parseCell(t, test.cell)
+			if len(lessons) != len(test.want) {
+				t.Fatalf("got %d lesson(s), want %d", len(lessons), len(test.want))
+			}
+
+			for i, w := range test.want {
+				got := want{
+					fullName:      lessons[i].FullName,
+					subjectName:   lessons[i].SubjectName,
+					groupLabel:    lessons[i].GroupLabel,
+					groupNumber:   lessons[i].GroupNumber,
+					groupTotal:    lessons[i].GroupTotal,
+					teacher:       lessons[i].TeacherDesignator,
+					teacherIndex:  lessons[i].TeacherIndex,
+					room:          lessons[i].RoomDesignator,
+					roomIndex:     lessons[i].RoomIndex,
+					division:      lessons[i].DivisionDesignator,
+					divisionIndex: lessons[i].DivisionIndex,
+				}
+				if got != w {
+					t.Errorf("lesson %d:\ngot  %+v\nwant %+v", i, got, w)
+				}
+			}
+		})
+	}
+}
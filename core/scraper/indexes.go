@@ -0,0 +1,224 @@
+// scraper/indexes.go
+package scraper
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// IndexLayout is the way a school publishes the lists of divisions, teachers and rooms
+type IndexLayout string
+
+const (
+	// Detects the layout of each list page
+	AutoLayout IndexLayout = "auto"
+	// Plain <a href="plany/o1.html"> links, one list page per resource type
+	LinksLayout IndexLayout = "links"
+	// The combined lista.html (or index.html) frameset, links live in the framed pages
+	FramesLayout IndexLayout = "frames"
+	// The index_o.html variant with <select> dropdowns of <option value="plany/o1.html">
+	DropdownLayout IndexLayout = "dropdown"
+	// The "kafelki" tiles, which open the pages from onclick handlers or data attributes
+	TilesLayout IndexLayout = "tiles"
+)
+
+func (l IndexLayout) String() string {
+	return string(l)
+}
+
+func ParseIndexLayout(s string) (IndexLayout, error) {
+	switch layout := IndexLayout(strings.ToLower(strings.TrimSpace(s))); layout {
+	case "":
+		return AutoLayout, nil
+	case AutoLayout, LinksLayout, FramesLayout, DropdownLayout, TilesLayout:
+		return layout, nil
+	}
+
+	return AutoLayout, fmt.Errorf("invalid index layout: %s", s)
+}
+
+// Attributes the tiles keep the page of a resource in
+var tileAttributes = []string{"onclick", "data-href", "data-url", "data-link"}
+
+func appendMatches(indexes []int64, regex *regexp.Regexp, s string) []int64 {
+	for _, match := range regex.FindAllStringSubmatch(s, -1) {
+		index, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			slog.Warn("error converting index", "match", match[1], "error", err)
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+func extractAttributeIndexes(doc *goquery.Document, selector, attribute string, regex *regexp.Regexp) []int64 {
+	var indexes []int64
+	doc.Find(selector).Each(func(i int, element *goquery.Selection) {
+		if value, exists := element.Attr(attribute); exists {
+			indexes = appendMatches(indexes, regex, value)
+		}
+	})
+	return indexes
+}
+
+func extractLinkIndexes(doc *goquery.Document, regex *regexp.Regexp) []int64 {
+	return extractAttributeIndexes(doc, "a", "href", regex)
+}
+
+func extractDropdownIndexes(doc *goquery.Document, regex *regexp.Regexp) []int64 {
+	return extractAttributeIndexes(doc, "select option", "value", regex)
+}
+
+func extractTileIndexes(doc *goquery.Document, regex *regexp.Regexp) []int64 {
+	var indexes []int64
+	for _, attribute := range tileAttributes {
+		indexes = append(indexes, extractAttributeIndexes(doc, "["+attribute+"]", attribute, regex)...)
+	}
+	return indexes
+}
+
+func frameSources(doc *goquery.Document) []string {
+	var sources []string
+	doc.Find("frame[src], iframe[src]").Each(func(i int, element *goquery.Selection) {
+		source, _ := element.Attr("src")
+		sources = append(sources, source)
+	})
+	return sources
+}
+
+// Opens a framed page, its source is relative to the page it's framed in
+func (s *School) openFrame(endpoint, source string) (*goquery.Document, string, error) {
+	pageURL, err := url.Parse(s.Config.BaseUrl + endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid page URL: %w", err)
+	}
+
+	frameURL, err := pageURL.Parse(source)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid frame source: %w", err)
+	}
+
+	frameEndpoint, isLocal := strings.CutPrefix(frameURL.String(), s.Config.BaseUrl)
+	if !isLocal {
+		return nil, "", fmt.Errorf("frame %s is outside of the base URL", frameURL)
+	}
+
+	doc, err := s.openDoc(frameEndpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return doc, frameEndpoint, nil
+}
+
+// Finds the indexes on the page with the given layout, auto tries the
+// layouts one by one and follows frames if the page itself has no indexes,
+// the endpoints of the framed pages that were opened are returned too
+func (s *School) findIndexes(doc *goquery.Document, endpoint string, regex *regexp.Regexp, layout IndexLayout, depth int) ([]int64, []string) {
+	switch layout {
+	case LinksLayout:
+		return extractLinkIndexes(doc, regex), nil
+	case DropdownLayout:
+		return extractDropdownIndexes(doc, regex), nil
+	case TilesLayout:
+		return extractTileIndexes(doc, regex), nil
+	}
+
+	if layout == AutoLayout {
+		for _, extract := range []func(*goquery.Document, *regexp.Regexp) []int64{
+			extractLinkIndexes,
+			extractDropdownIndexes,
+			extractTileIndexes,
+		} {
+			if indexes := extract(doc, regex); len(indexes) > 0 {
+				return indexes, nil
+			}
+		}
+	}
+
+	// Framesets of framesets are as deep as Optivum goes
+	if depth >= 2 {
+		return nil, nil
+	}
+
+	var indexes []int64
+	var frames []string
+	for _, source := range frameSources(doc) {
+		frameDoc, frameEndpoint, err := s.openFrame(endpoint, source)
+		if err != nil {
+			s.logger().Warn("error opening frame", "url", source, "error", err)
+			continue
+		}
+
+		frameIndexes, frameFrames := s.findIndexes(frameDoc, frameEndpoint, regex, AutoLayout, depth+1)
+		indexes = append(indexes, frameIndexes...)
+		frames = append(append(frames, frameEndpoint), frameFrames...)
+	}
+
+	return indexes, frames
+}
+
+// Finds the indexes on the list page with the configured layout, sorted
+// and without duplicates, along with the framed pages that were opened
+func (s *School) findListIndexes(doc *goquery.Document, listEndpoint string, regex *regexp.Regexp) ([]int64, []string) {
+	layout, err := ParseIndexLayout(s.Config.IndexLayout)
+	if err != nil {
+		layout = AutoLayout
+	}
+
+	indexes, frames := s.findIndexes(doc, listEndpoint, regex, layout, 0)
+	slices.Sort(indexes)
+
+	return slices.Compact(indexes), frames
+}
+
+// Discovers the indexes listed on the list page, only keeping those whose page can be opened
+func (s *School) scrapeIndexes(resourceType ResourceType, listEndpoint string, regex *regexp.Regexp, staticIndexes []int64, makeEndpoint func(int64) string) ([]int64, error) {
+	layout, err := ParseIndexLayout(s.Config.IndexLayout)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.openDoc(listEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error opening document: %w", err)
+	}
+
+	indexes := []int64{}
+	seen := make(map[int64]bool)
+	for _, index := range staticIndexes {
+		if !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+
+	found, _ := s.findIndexes(doc, listEndpoint, regex, layout, 0)
+	if len(found) == 0 {
+		s.logger().Warn("no indexes found", "resource", resourceType, "layout", layout)
+	}
+
+	for _, index := range found {
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+
+		endpoint := makeEndpoint(index)
+		if !s.checkURL(s.Config.BaseUrl + endpoint) {
+			s.logger().Warn("error opening resource page", "resource", resourceType, "url", s.Config.BaseUrl+endpoint)
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"smuggr.xyz/goptivum/common/config"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	linksPage    = `<html><body><a href="plany/o1.html" target="plan">1A</a><a href="plany/o2.html" target="plan">2TI</a><a href="plany/n1.html">J.Kowalski (JK)</a></body></html>`
+	dropdownPage = `<html><body><form><select name="oddzialy" onchange="window.location=this.value"><option value="">oddziały</option><option value="plany/o1.html">1A</option><option value="plany/o2.html">2TI</option></select></form></body></html>`
+	tilesPage    = `<html><body><div class="kafelek" onclick="location.href='plany/o1.html'">1A</div><div class="kafelek" data-href="plany/o2.html">2TI</div></body></html>`
+	framesPage   = `<html><frameset cols="200,*"><frame name="list" src="plany/lista.html"><frame name="plan" src="plany/o1.html"></frameset></html>`
+	framedList   = `<html><body><a href="o1.html" target="plan">1A</a><a href="o2.html" target="plan">2TI</a></body></html>`
+)
+
+func parsePage(t *testing.T, page string) *goquery.Document {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("error parsing page: %v", err)
+	}
+	return doc
+}
+
+func TestFindIndexes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/plany/lista.html":
+			fmt.Fprint(w, framedList)
+		case "/plany/o1.html", "/plany/o2.html":
+			fmt.Fprint(w, `<span class="tytulnapis">1A technikum</span>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	school := NewSchool(config.SchoolConfig{ID: "test"})
+	school.Config.BaseUrl = server.URL + "/"
+	school.Client = server.Client()
+
+	tests := []struct {
+		name   string
+		page   string
+		layout IndexLayout
+		want   []int64
+	}{
+		{"links", linksPage, LinksLayout, []int64{1, 2}},
+		{"dropdown", dropdownPage, DropdownLayout, []int64{1, 2}},
+		{"tiles", tilesPage, TilesLayout, []int64{1, 2}},
+		{"frames", framesPage, FramesLayout, []int64{1, 2}},
+		{"auto links", linksPage, AutoLayout, []int64{1, 2}},
+		{"auto dropdown", dropdownPage, AutoLayout, []int64{1, 2}},
+		{"auto tiles", tilesPage, AutoLayout, []int64{1, 2}},
+		{"auto frames", framesPage, AutoLayout, []int64{1, 2}},
+		{"wrong layout", dropdownPage, LinksLayout, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := school.findIndexes(parsePage(t, test.page), "index.html", DivisionIndexRegex, test.layout, 0)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
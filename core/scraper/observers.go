@@ -3,6 +3,8 @@ package scraper
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,110 +13,100 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-func ObserveDivisions(refreshChan *chan int64) {
-	fmt.Println("observing divisions")
+// Observes a list page, its hash is built from the indexes found with the
+// configured layout, so only added or removed items count as a change
+func (s *School) newListObserver(listEndpoint string, regex *regexp.Regexp, callbackFunc func() error) *observer.Observer {
+	o := observer.NewObserver(0, s.Config.BaseUrl+listEndpoint, 1*time.Second, nil, callbackFunc)
 
-	refreshDivisionsObservers := func() {
-		DivisionsScraperResource.RefreshObservers()
+	o.ExtractContent = func(doc *goquery.Document) string {
+		indexes, frames := s.findListIndexes(doc, listEndpoint, regex)
+		// The frameset stays the same when the framed list changes
+		o.Unconditional = len(frames) > 0
+
+		content := make([]string, 0, len(indexes))
+		for _, index := range indexes {
+			content = append(content, strconv.FormatInt(index, 10))
+		}
+		return strings.Join(content, " ")
 	}
 
-	extractFunc := func(doc *goquery.Document) string {
-		var hrefs []string
-		doc.Find("table a").Each(func(i int, s *goquery.Selection) {
-			href, exists := s.Attr("href")
-			if exists {
-				hrefs = append(hrefs, href)
-			}
-		})
-		return strings.Join(hrefs, " ")
+	return o
+}
+
+func (s *School) ObserveDivisions(refreshChan *chan RefreshEvent) {
+	s.Divisions.logger().Info("observing list")
+
+	refreshDivisionsObservers := func() {
+		s.Divisions.RefreshObservers()
 	}
 
-	callbackFunc := func() {
-		fmt.Println("divisions list changed!")
-		divisionsIndexes, err := ScrapeDivisionsIndexes()
+	callbackFunc := func() error {
+		s.Divisions.logger().Info("list changed")
+		divisionsIndexes, err := s.ScrapeDivisionsIndexes()
 		if err != nil {
-			fmt.Printf("error scraping divisions indexes: %v\n", err)
-			return
+			return fmt.Errorf("error scraping indexes: %w", err)
 		}
-		DivisionsScraperResource.UpdateIndexes(divisionsIndexes)
+		s.Divisions.UpdateIndexes(divisionsIndexes)
 		refreshDivisionsObservers()
+		*refreshChan <- RefreshEvent{Type: ListChangedRefresh, Resource: DivisionResource}
+		return nil
 	}
 
-	DivisionsScraperResource.StartHub()
+	s.Divisions.StartHub()
 
-	DivisionsScraperResource.Observer = observer.NewObserver(0, Config.BaseUrl+Config.Endpoints.DivisionsList, 1*time.Second, extractFunc, callbackFunc)
-	DivisionsScraperResource.Hub.AddObserver(DivisionsScraperResource.Observer)
+	s.Divisions.Observer = s.newListObserver(s.Config.Endpoints.DivisionsList, DivisionIndexRegex, callbackFunc)
+	s.Divisions.Hub.AddObserver(s.Divisions.Observer)
 	refreshDivisionsObservers()
 }
 
-func ObserveTeachers(refreshChan *chan int64) {
-	fmt.Println("observing teachers")
+func (s *School) ObserveTeachers(refreshChan *chan RefreshEvent) {
+	s.Teachers.logger().Info("observing list")
 
 	refreshTeachersObservers := func() {
-		TeachersScraperResource.RefreshObservers()
-	}
-
-	extractFunc := func(doc *goquery.Document) string {
-		var hrefs []string
-		doc.Find("table a").Each(func(i int, s *goquery.Selection) {
-			href, exists := s.Attr("href")
-			if exists {
-				hrefs = append(hrefs, href)
-			}
-		})
-		return strings.Join(hrefs, " ")
+		s.Teachers.RefreshObservers()
 	}
 
-	callbackFunc := func() {
-		fmt.Println("teachers list changed!")
-		teachersIndexes, err := ScrapeTeachersIndexes()
+	callbackFunc := func() error {
+		s.Teachers.logger().Info("list changed")
+		teachersIndexes, err := s.ScrapeTeachersIndexes()
 		if err != nil {
-			fmt.Printf("error scraping teachers indexes: %v\n", err)
-			return
+			return fmt.Errorf("error scraping indexes: %w", err)
 		}
-		TeachersScraperResource.UpdateIndexes(teachersIndexes)
+		s.Teachers.UpdateIndexes(teachersIndexes)
 		refreshTeachersObservers()
+		*refreshChan <- RefreshEvent{Type: ListChangedRefresh, Resource: TeacherResource}
+		return nil
 	}
 
-	TeachersScraperResource.StartHub()
+	s.Teachers.StartHub()
 
-	TeachersScraperResource.Observer = observer.NewObserver(0, Config.BaseUrl+Config.Endpoints.TeachersList, 1*time.Second, extractFunc, callbackFunc)
-	TeachersScraperResource.AddObserver(TeachersScraperResource.Observer)
+	s.Teachers.Observer = s.newListObserver(s.Config.Endpoints.TeachersList, TeacherIndexRegex, callbackFunc)
+	s.Teachers.AddObserver(s.Teachers.Observer)
 	refreshTeachersObservers()
 }
 
-func ObserveRooms(refreshChan *chan int64) {
-	fmt.Println("observing rooms")
+func (s *School) ObserveRooms(refreshChan *chan RefreshEvent) {
+	s.Rooms.logger().Info("observing list")
 
 	refreshRoomsObservers := func() {
-		RoomsScraperResource.RefreshObservers()
-	}
-
-	extractFunc := func(doc *goquery.Document) string {
-		var hrefs []string
-		doc.Find("a").Each(func(i int, s *goquery.Selection) {
-			href, exists := s.Attr("href")
-			if exists {
-				hrefs = append(hrefs, href)
-			}
-		})
-		return strings.Join(hrefs, " ")
+		s.Rooms.RefreshObservers()
 	}
 
-	callbackFunc := func() {
-		fmt.Println("rooms list changed!")
-		roomsIndexes, err := ScrapeRoomsIndexes()
+	callbackFunc := func() error {
+		s.Rooms.logger().Info("list changed")
+		roomsIndexes, err := s.ScrapeRoomsIndexes()
 		if err != nil {
-			fmt.Printf("error scraping rooms indexes: %v\n", err)
-			return
+			return fmt.Errorf("error scraping indexes: %w", err)
 		}
-		RoomsScraperResource.UpdateIndexes(roomsIndexes)
+		s.Rooms.UpdateIndexes(roomsIndexes)
 		refreshRoomsObservers()
+		*refreshChan <- RefreshEvent{Type: ListChangedRefresh, Resource: RoomResource}
+		return nil
 	}
 
-	RoomsScraperResource.StartHub()
+	s.Rooms.StartHub()
 
-	RoomsScraperResource.Observer = observer.NewObserver(0, Config.BaseUrl+Config.Endpoints.RoomsList, 1*time.Second, extractFunc, callbackFunc)
-	RoomsScraperResource.Hub.AddObserver(RoomsScraperResource.Observer)
+	s.Rooms.Observer = s.newListObserver(s.Config.Endpoints.RoomsList, RoomIndexRegex, callbackFunc)
+	s.Rooms.Hub.AddObserver(s.Rooms.Observer)
 	refreshRoomsObservers()
 }
@@ -0,0 +1,185 @@
+// optivumtest/optivumtest.go
+
+// Package optivumtest serves Optivum-like schedule pages generated from a
+// models.School, for testing the scraper without the school's site
+package optivumtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ListEndpoint     = "lista.html"
+	DivisionEndpoint = "plany/o%d.html"
+	TeacherEndpoint  = "plany/n%d.html"
+	RoomEndpoint     = "plany/s%d.html"
+
+	// Framed by lista.html in the frames layout
+	FramedListEndpoint = "plany/lista.html"
+	BlankEndpoint      = "plany/pusty.html"
+)
+
+// ListLayout is the way lista.html lists the schedule pages
+type ListLayout string
+
+const (
+	LinksList    ListLayout = "links"
+	DropdownList ListLayout = "dropdown"
+	TilesList    ListLayout = "tiles"
+	// lista.html is a frameset, the links are in plany/lista.html
+	FramesList ListLayout = "frames"
+)
+
+var pageRegex = regexp.MustCompile(`^/plany/([ons])(\d+)\.html$`)
+
+// Server serves lista.html and the oN.html, nN.html and sN.html schedule pages,
+// pages are rendered on every request so changes to the school show up immediately
+type Server struct {
+	*httptest.Server
+
+	mu      sync.RWMutex
+	school  *models.School
+	periods *models.Periods
+	layout  ListLayout
+	hits    map[string]int
+	down    bool
+}
+
+// NewServer starts serving the school, which is copied so the caller can't
+// change it behind the server's back, use Update instead
+func NewServer(school *models.School) *Server {
+	s := &Server{
+		school:  proto.Clone(school).(*models.School),
+		periods: DefaultPeriods(8),
+		layout:  LinksList,
+		hits:    make(map[string]int),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// BaseURL is the URL to configure as the scraper's base URL
+func (s *Server) BaseURL() string {
+	return s.URL + "/"
+}
+
+// Update changes the served school
+func (s *Server) Update(update func(school *models.School)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(s.school)
+}
+
+// SetPeriods changes the bell schedule used for every page
+func (s *Server) SetPeriods(periods *models.Periods) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.periods = proto.Clone(periods).(*models.Periods)
+}
+
+// SetListLayout changes the way lista.html lists the schedule pages
+func (s *Server) SetListLayout(layout ListLayout) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.layout = layout
+}
+
+// SetDown makes the server answer every request with 503 Service Unavailable
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+// Hits returns how many times the page (e.g. "/plany/o1.html") was requested
+func (s *Server) Hits(path string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hits[path]
+}
+
+func (s *Server) render(path string) ([]byte, bool) {
+	switch {
+	case path == "/"+ListEndpoint && s.layout == FramesList:
+		return renderFrameset(), true
+	case path == "/"+ListEndpoint:
+		return renderList(s.school, s.layout, "plany/"), true
+	case path == "/"+FramedListEndpoint && s.layout == FramesList:
+		return renderList(s.school, LinksList, ""), true
+	case path == "/"+BlankEndpoint && s.layout == FramesList:
+		return []byte(fmt.Sprintf(pageHeader, "") + pageFooter), true
+	}
+
+	match := pageRegex.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+
+	index, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	switch match[1] {
+	case "o":
+		for _, division := range s.school.Divisions {
+			if division.Index == index {
+				return renderSchedulePage(divisionPage, divisionTitle(division), division.Schedule, s.periods), true
+			}
+		}
+	case "n":
+		for _, teacher := range s.school.Teachers {
+			if teacher.Index == index {
+				return renderSchedulePage(teacherPage, teacherTitle(teacher), teacher.Schedule, s.periods), true
+			}
+		}
+	case "s":
+		for _, room := range s.school.Rooms {
+			if room.Index == index {
+				return renderSchedulePage(roomPage, roomTitle(room), room.Schedule, s.periods), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Pages carry an ETag, so conditional requests get a 304 until the page changes
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.hits[r.URL.Path]++
+	down := s.down
+	page, ok := s.render(r.URL.Path)
+	s.mu.Unlock()
+
+	if down {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	sum := sha256.Sum256(page)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+
+	http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(page))
+}
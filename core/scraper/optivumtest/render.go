@@ -0,0 +1,259 @@
+// optivumtest/render.go
+package optivumtest
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+var dayNames = []string{"Poniedziałek", "Wtorek", "Środa", "Czwartek", "Piątek", "Sobota", "Niedziela"}
+
+type pageType int
+
+const (
+	divisionPage pageType = iota
+	teacherPage
+	roomPage
+)
+
+// Returns the default bell schedule: 45 minute lessons every 55 minutes from 8:00
+func DefaultPeriods(count int) *models.Periods {
+	periods := &models.Periods{}
+	for i := 0; i < count; i++ {
+		start := 8*60 + int64(i)*55
+		end := start + 45
+		periods.Periods = append(periods.Periods, &models.Period{
+			Number: int64(i + 1),
+			TimeRange: &models.TimeRange{
+				Start: &models.Timestamp{Hour: start / 60, Minute: start % 60},
+				End:   &models.Timestamp{Hour: end / 60, Minute: end % 60},
+			},
+		})
+	}
+	return periods
+}
+
+const pageHeader = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN">
+<html>
+<head>
+<meta http-equiv="Content-Type" content="text/html; charset=UTF-8">
+<title>Plan lekcji %s</title>
+<link rel="stylesheet" href="../css/plan.css" type="text/css">
+</head>
+<body>
+`
+
+const pageFooter = `</body>
+</html>
+`
+
+func writeLink(b *strings.Builder, prefix string, index int64, class, text string) {
+	if index == 0 {
+		fmt.Fprintf(b, `<span class="%s">%s</span>`, class, html.EscapeString(text))
+		return
+	}
+	fmt.Fprintf(b, `<a href="%s%d.html" class="%s">%s</a>`, prefix, index, class, html.EscapeString(text))
+}
+
+func subjectOf(lesson *models.Lesson) string {
+	if lesson.SubjectName != "" {
+		return lesson.SubjectName
+	}
+	return lesson.FullName
+}
+
+// Renders a lesson the way Optivum does on the given type of page
+func writeLesson(b *strings.Builder, page pageType, lesson *models.Lesson) {
+	group := ""
+	if lesson.GroupLabel != "" {
+		group = "-" + html.EscapeString(lesson.GroupLabel)
+	}
+
+	switch page {
+	case divisionPage:
+		fmt.Fprintf(b, `<span class="p">%s</span> `, html.EscapeString(lesson.FullName))
+		writeLink(b, "n", lesson.TeacherIndex, "n", lesson.TeacherDesignator)
+		b.WriteString(" ")
+		writeLink(b, "s", lesson.RoomIndex, "s", lesson.RoomDesignator)
+	case teacherPage:
+		writeLink(b, "o", lesson.DivisionIndex, "o", lesson.DivisionDesignator)
+		fmt.Fprintf(b, `%s <span class="p">%s</span> `, group, html.EscapeString(subjectOf(lesson)))
+		writeLink(b, "s", lesson.RoomIndex, "s", lesson.RoomDesignator)
+	case roomPage:
+		writeLink(b, "n", lesson.TeacherIndex, "n", lesson.TeacherDesignator)
+		b.WriteString(" ")
+		writeLink(b, "o", lesson.DivisionIndex, "o", lesson.DivisionDesignator)
+		fmt.Fprintf(b, `%s <span class="p">%s</span>`, group, html.EscapeString(subjectOf(lesson)))
+	}
+}
+
+// Returns the lessons of every period of the day, groups without a period
+// number are placed by their position
+func lessonsByPeriod(scheduleDay *models.ScheduleDay) map[int64][]*models.Lesson {
+	lessons := make(map[int64][]*models.Lesson)
+	for i, lessonGroup := range scheduleDay.LessonGroups {
+		period := lessonGroup.Period
+		if period == 0 {
+			period = int64(i + 1)
+		}
+		lessons[period] = append(lessons[period], lessonGroup.Lessons...)
+	}
+	return lessons
+}
+
+func renderSchedulePage(page pageType, title string, schedule *models.Schedule, periods *models.Periods) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, pageHeader, html.EscapeString(title))
+	fmt.Fprintf(&b, "<table border=\"0\" cellpadding=\"0\" cellspacing=\"0\" width=\"100%%\"><tr><td class=\"tytul\"><span class=\"tytulnapis\">%s</span></td></tr></table>\n", html.EscapeString(title))
+
+	days := 5
+	if schedule != nil && len(schedule.ScheduleDays) > 0 {
+		days = len(schedule.ScheduleDays)
+	}
+
+	var byDay []map[int64][]*models.Lesson
+	for day := 0; day < days; day++ {
+		if schedule != nil && day < len(schedule.ScheduleDays) {
+			byDay = append(byDay, lessonsByPeriod(schedule.ScheduleDays[day]))
+		} else {
+			byDay = append(byDay, map[int64][]*models.Lesson{})
+		}
+	}
+
+	b.WriteString("<table border=\"1\" cellspacing=\"0\" cellpadding=\"4\" class=\"tabela\">\n")
+	b.WriteString("<tr>\n<th>Nr</th>\n<th>Godz</th>\n")
+	for day := 0; day < days; day++ {
+		fmt.Fprintf(&b, "<th>%s</th>\n", dayNames[day%len(dayNames)])
+	}
+	b.WriteString("</tr>\n")
+
+	for _, period := range periods.Periods {
+		start := period.TimeRange.Start
+		end := period.TimeRange.End
+
+		b.WriteString("<tr>\n")
+		fmt.Fprintf(&b, "<td class=\"nr\">%d</td>\n", period.Number)
+		fmt.Fprintf(&b, "<td class=\"g\">%2d:%02d-%2d:%02d</td>\n", start.Hour, start.Minute, end.Hour, end.Minute)
+
+		for day := 0; day < days; day++ {
+			lessons := byDay[day][period.Number]
+			if len(lessons) == 0 {
+				b.WriteString("<td class=\"l\">&nbsp;</td>\n")
+				continue
+			}
+
+			b.WriteString("<td class=\"l\">")
+			for i, lesson := range lessons {
+				if i > 0 {
+					b.WriteString("<br>")
+				}
+				if len(lessons) > 1 {
+					b.WriteString(`<span style="font-size:85%">`)
+				}
+				writeLesson(&b, page, lesson)
+				if len(lessons) > 1 {
+					b.WriteString("</span>")
+				}
+			}
+			b.WriteString("</td>\n")
+		}
+
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("</table>\n")
+	b.WriteString(pageFooter)
+
+	return []byte(b.String())
+}
+
+type listItem struct {
+	href string
+	text string
+}
+
+// Renders the lists of divisions, teachers and rooms in the given layout,
+// the links are relative to the directory of the schedule pages
+func renderList(school *models.School, layout ListLayout, directory string) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, pageHeader, "- lista")
+
+	writeSection := func(name string, items []listItem) {
+		switch layout {
+		case DropdownList:
+			fmt.Fprintf(&b, "<form><select name=\"%s\" onchange=\"window.location=this.value\">\n<option value=\"\">%s</option>\n", strings.ToLower(name), name)
+			for _, item := range items {
+				fmt.Fprintf(&b, "<option value=\"%s\">%s</option>\n", item.href, html.EscapeString(item.text))
+			}
+			b.WriteString("</select></form>\n")
+		case TilesList:
+			fmt.Fprintf(&b, "<h4>%s</h4>\n", name)
+			for _, item := range items {
+				fmt.Fprintf(&b, "<div class=\"kafelek\" onclick=\"location.href='%s'\">%s</div>\n", item.href, html.EscapeString(item.text))
+			}
+		default:
+			fmt.Fprintf(&b, "<h4>%s</h4>\n<ul>\n", name)
+			for _, item := range items {
+				fmt.Fprintf(&b, "<li><a href=\"%s\" target=\"plan\">%s</a></li>\n", item.href, html.EscapeString(item.text))
+			}
+			b.WriteString("</ul>\n")
+		}
+	}
+
+	var items []listItem
+	for _, division := range school.Divisions {
+		items = append(items, listItem{fmt.Sprintf("%so%d.html", directory, division.Index), division.Designator})
+	}
+	writeSection("Oddziały", items)
+
+	items = nil
+	for _, teacher := range school.Teachers {
+		items = append(items, listItem{fmt.Sprintf("%sn%d.html", directory, teacher.Index), fmt.Sprintf("%s (%s)", teacher.FullName, teacher.Designator)})
+	}
+	writeSection("Nauczyciele", items)
+
+	items = nil
+	for _, room := range school.Rooms {
+		items = append(items, listItem{fmt.Sprintf("%ss%d.html", directory, room.Index), room.Designator})
+	}
+	writeSection("Sale", items)
+
+	b.WriteString(pageFooter)
+
+	return []byte(b.String())
+}
+
+// The frameset frames the list next to an empty plan
+func renderFrameset() []byte {
+	return []byte(`<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Frameset//EN">
+<html>
+<head>
+<meta http-equiv="Content-Type" content="text/html; charset=UTF-8">
+<title>Plan lekcji</title>
+</head>
+<frameset cols="200,*">
+<frame name="list" src="` + FramedListEndpoint + `">
+<frame name="plan" src="` + BlankEndpoint + `">
+</frameset>
+</html>
+`)
+}
+
+// Titles follow Optivum: "<designator> <name>" for divisions and
+// rooms, "<name> (<designator>)" for teachers
+func divisionTitle(division *models.Division) string {
+	return strings.TrimSpace(division.Designator + " " + division.FullName)
+}
+
+func teacherTitle(teacher *models.Teacher) string {
+	return fmt.Sprintf("%s (%s)", teacher.FullName, teacher.Designator)
+}
+
+func roomTitle(room *models.Room) string {
+	return strings.TrimSpace(room.Designator + " " + room.FullName)
+}
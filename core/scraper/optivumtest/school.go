@@ -0,0 +1,113 @@
+// optivumtest/school.go
+package optivumtest
+
+import (
+	"smuggr.xyz/goptivum/common/models"
+)
+
+func findTeacher(school *models.School, index int64) *models.Teacher {
+	for _, teacher := range school.Teachers {
+		if teacher.Index == index {
+			return teacher
+		}
+	}
+	return nil
+}
+
+func findRoom(school *models.School, index int64) *models.Room {
+	for _, room := range school.Rooms {
+		if room.Index == index {
+			return room
+		}
+	}
+	return nil
+}
+
+func newSchedule(days int) *models.Schedule {
+	schedule := &models.Schedule{}
+	for i := 0; i < days; i++ {
+		schedule.ScheduleDays = append(schedule.ScheduleDays, &models.ScheduleDay{})
+	}
+	return schedule
+}
+
+func addLesson(schedule *models.Schedule, day int, period int64, lesson *models.Lesson) {
+	scheduleDay := schedule.ScheduleDays[day]
+	for _, lessonGroup := range scheduleDay.LessonGroups {
+		if lessonGroup.Period == period {
+			lessonGroup.Lessons = append(lessonGroup.Lessons, lesson)
+			return
+		}
+	}
+
+	position := len(scheduleDay.LessonGroups)
+	for i, lessonGroup := range scheduleDay.LessonGroups {
+		if lessonGroup.Period > period {
+			position = i
+			break
+		}
+	}
+
+	scheduleDay.LessonGroups = append(scheduleDay.LessonGroups, nil)
+	copy(scheduleDay.LessonGroups[position+1:], scheduleDay.LessonGroups[position:])
+	scheduleDay.LessonGroups[position] = &models.LessonGroup{
+		Period:  period,
+		Lessons: []*models.Lesson{lesson},
+	}
+}
+
+// DeriveSchedules rebuilds the schedules of the teachers and rooms from the
+// lessons of the divisions, so all the pages of the school agree with each other.
+// Lessons are matched by the teacher and room indexes, designators and division
+// fields of the lessons are filled in along the way
+func DeriveSchedules(school *models.School) {
+	days := 5
+	for _, division := range school.Divisions {
+		if division.Schedule != nil {
+			days = max(days, len(division.Schedule.ScheduleDays))
+		}
+	}
+
+	for _, teacher := range school.Teachers {
+		teacher.Schedule = newSchedule(days)
+	}
+	for _, room := range school.Rooms {
+		room.Schedule = newSchedule(days)
+	}
+
+	for _, division := range school.Divisions {
+		if division.Schedule == nil {
+			continue
+		}
+
+		for day, scheduleDay := range division.Schedule.ScheduleDays {
+			for i, lessonGroup := range scheduleDay.LessonGroups {
+				if lessonGroup.Period == 0 {
+					lessonGroup.Period = int64(i + 1)
+				}
+
+				for _, lesson := range lessonGroup.Lessons {
+					lesson.DivisionIndex = division.Index
+					lesson.DivisionDesignator = division.Designator
+
+					teacher := findTeacher(school, lesson.TeacherIndex)
+					if teacher != nil {
+						lesson.TeacherDesignator = teacher.Designator
+					}
+
+					room := findRoom(school, lesson.RoomIndex)
+					if room != nil {
+						lesson.RoomDesignator = room.Designator
+					}
+
+					if teacher != nil {
+						addLesson(teacher.Schedule, day, lessonGroup.Period, lesson)
+					}
+					if room != nil {
+						addLesson(room.Schedule, day, lessonGroup.Period, lesson)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// scraper/polling.go
+package scraper
+
+import (
+	"fmt"
+	"time"
+
+	"smuggr.xyz/goptivum/core/hub"
+	"smuggr.xyz/goptivum/core/timetable"
+)
+
+// Returns the base interval of the observer of the given schedule page, when
+// it isn't configured the observers are spread out by their index
+func (s *School) observerInterval(index int64) time.Duration {
+	if s.Config.Polling.Interval > 0 {
+		return s.Config.Polling.Interval
+	}
+
+	return time.Duration((index+1)/10+5) * time.Second
+}
+
+// Caps the backoff and slowdown when max_interval isn't configured
+const defaultMaxInterval = 30 * time.Minute
+
+func (s *School) newPollingPolicy() (*hub.Policy, error) {
+	policy := &hub.Policy{
+		MaxInterval:   s.Config.Polling.MaxInterval,
+		BackoffFactor: s.Config.Polling.BackoffFactor,
+		StableAfter:   s.Config.Polling.StableAfter,
+		StableFactor:  s.Config.Polling.StableFactor,
+		Jitter:        s.Config.Polling.Jitter,
+		Location:      s.Location,
+	}
+
+	if policy.MaxInterval == 0 {
+		policy.MaxInterval = max(defaultMaxInterval, s.Config.Polling.Interval)
+	}
+	if policy.MaxInterval < s.Config.Polling.Interval {
+		return nil, fmt.Errorf("polling max interval %s is shorter than the interval %s", policy.MaxInterval, s.Config.Polling.Interval)
+	}
+
+	if policy.Jitter < 0 || policy.Jitter >= 1 {
+		return nil, fmt.Errorf("invalid polling jitter, expected 0 - 1: %v", policy.Jitter)
+	}
+
+	for i, windowConfig := range s.Config.Polling.Windows {
+		window := &hub.Window{
+			Name:     windowConfig.Name,
+			Interval: windowConfig.Interval,
+		}
+
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("window %d", i)
+		}
+
+		for _, day := range windowConfig.Days {
+			weekday, err := hub.ParseWeekday(day)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing polling window %s: %w", window.Name, err)
+			}
+			window.Days = append(window.Days, weekday)
+		}
+
+		for _, dates := range windowConfig.Dates {
+			dateRange, err := hub.ParseDateRange(dates, policy.Location)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing polling window %s: %w", window.Name, err)
+			}
+			window.Dates = append(window.Dates, dateRange)
+		}
+
+		if windowConfig.Start != "" || windowConfig.End != "" {
+			start, err := timetable.ParseClock(windowConfig.Start)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing polling window %s: %w", window.Name, err)
+			}
+
+			end, err := timetable.ParseClock(windowConfig.End)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing polling window %s: %w", window.Name, err)
+			}
+
+			window.Start = start
+			window.End = end
+			window.HasHours = true
+		}
+
+		policy.Windows = append(policy.Windows, window)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/config"
+)
+
+func TestPollingPolicyMaxInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    time.Duration
+		maxInterval time.Duration
+		want        time.Duration
+		wantErr     bool
+	}{
+		{"default", 0, 0, defaultMaxInterval, false},
+		{"default above the interval", time.Minute, 0, defaultMaxInterval, false},
+		{"configured", time.Minute, 10 * time.Minute, 10 * time.Minute, false},
+		{"equal to the interval", time.Minute, time.Minute, time.Minute, false},
+		{"shorter than the interval", time.Minute, 30 * time.Second, 0, true},
+		{"default below the interval", time.Hour, 0, time.Hour, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			school := NewSchool(config.SchoolConfig{ID: "test"})
+			school.Config.Polling.Interval = test.interval
+			school.Config.Polling.MaxInterval = test.maxInterval
+
+			policy, err := school.newPollingPolicy()
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got max interval %s, want an error", policy.MaxInterval)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error creating policy: %v", err)
+			}
+			if policy.MaxInterval != test.want {
+				t.Errorf("max interval: got %s, want %s", policy.MaxInterval, test.want)
+			}
+		})
+	}
+}
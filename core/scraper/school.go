@@ -0,0 +1,100 @@
+// scraper/school.go
+package scraper
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/utils"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/source"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// School is a single Optivum site, it has its own config, resources,
+// observer hubs and datastore keys
+type School struct {
+	ID       string
+	Name     string
+	Config   config.ScraperConfig
+	Store    *datastore.Store
+	Client   *http.Client
+	Location *time.Location
+
+	Divisions     *ScraperResource
+	Teachers      *ScraperResource
+	Rooms         *ScraperResource
+	Substitutions *ScraperResource
+
+	mu    sync.Mutex
+	quit  chan struct{}
+	ready atomic.Bool
+}
+
+var (
+	Schools       []*School
+	DefaultSchool *School
+)
+
+func NewSchool(schoolConfig config.SchoolConfig) *School {
+	school := &School{
+		ID:       schoolConfig.ID,
+		Name:     schoolConfig.Name,
+		Config:   schoolConfig.Scraper,
+		Store:    datastore.NewStore(schoolConfig.KeyPrefix),
+		Client:   source.NewClient(schoolConfig.Scraper.IgnoreCertificates, 20*time.Second),
+		Location: utils.LoadLocation(schoolConfig.Scraper.Timezone),
+		quit:     make(chan struct{}),
+	}
+
+	school.Divisions = NewScraperResource(school, DivisionIndexRegex, DivisionResource)
+	school.Teachers = NewScraperResource(school, TeacherIndexRegex, TeacherResource)
+	school.Rooms = NewScraperResource(school, RoomIndexRegex, RoomResource)
+	school.Substitutions = NewScraperResource(school, nil, SubstitutionResource)
+
+	return school
+}
+
+// Returns the school with the given ID, nil if there's none
+func GetSchool(id string) *School {
+	for _, school := range Schools {
+		if school.ID == id {
+			return school
+		}
+	}
+
+	return nil
+}
+
+func (s *School) String() string {
+	return s.ID
+}
+
+func (s *School) logger() *slog.Logger {
+	return slog.With("school", s.ID)
+}
+
+func (s *School) openDoc(endpoint string) (*goquery.Document, error) {
+	return utils.OpenDoc(s.Client, s.Config.BaseUrl, endpoint)
+}
+
+func (s *School) checkURL(url string) bool {
+	return utils.CheckURL(s.Client, url)
+}
+
+func (s *School) Cleanup() {
+	s.logger().Info("cleaning scraper")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.quit)
+	s.Divisions.StopHub()
+	s.Teachers.StopHub()
+	s.Rooms.StopHub()
+	s.Substitutions.StopHub()
+}
@@ -3,36 +3,59 @@ package scraper
 
 import (
 	"fmt"
+	"log/slog"
 	"regexp"
-	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"smuggr.xyz/goptivum/common/config"
 	"smuggr.xyz/goptivum/common/models"
-	"smuggr.xyz/goptivum/common/utils"
-	"smuggr.xyz/goptivum/core/datastore"
 	"smuggr.xyz/goptivum/core/hub"
 	"smuggr.xyz/goptivum/core/observer"
-
-	"github.com/PuerkitoBio/goquery"
 )
 
 // TODO: Add the ability to add more resource indexes to the scraper, (SKat)
 
-var Config config.ScraperConfig
-
 type ResourceType string
 
 const (
 	DivisionResource ResourceType = "division"
 	TeacherResource  ResourceType = "teacher"
 	RoomResource     ResourceType = "room"
+
+	SubstitutionResource ResourceType = "substitution"
 )
 
 func (t ResourceType) String() string {
 	return string(t)
 }
 
+type RefreshType string
+
+const (
+	UpdatedRefresh     RefreshType = "updated"
+	RemovedRefresh     RefreshType = "removed"
+	ListChangedRefresh RefreshType = "list.changed"
+)
+
+// RefreshEvent is sent on the resource's RefreshChan once an item has been
+// saved or removed, or once the list of items has changed (without an index)
+type RefreshEvent struct {
+	Type     RefreshType
+	Resource ResourceType
+	Index    int64
+}
+
+// Returns the name of the event, e.g. division.updated or list.changed
+func (e RefreshEvent) Name() string {
+	if e.Type == ListChangedRefresh {
+		return string(e.Type)
+	}
+
+	return e.Resource.String() + "." + string(e.Type)
+}
+
 type MetadataType string
 
 const (
@@ -47,11 +70,13 @@ type ScraperResource struct {
 	Hub         *hub.Hub
 	IndexRegex  *regexp.Regexp
 	Mu          *sync.RWMutex
-	RefreshChan chan int64
+	RefreshChan chan RefreshEvent
 	Type        ResourceType
+	School      *School
+	lastScraped atomic.Int64
 }
 
-func NewScraperResource(indexRegex *regexp.Regexp, resourceType ResourceType) *ScraperResource {
+func NewScraperResource(school *School, indexRegex *regexp.Regexp, resourceType ResourceType) *ScraperResource {
 	return &ScraperResource{
 		Indexes: []int64{},
 		Metadata: &models.Metadata{
@@ -62,13 +87,19 @@ func NewScraperResource(indexRegex *regexp.Regexp, resourceType ResourceType) *S
 		Hub:         &hub.Hub{},
 		IndexRegex:  indexRegex,
 		Mu:          &sync.RWMutex{},
-		RefreshChan: make(chan int64),
+		RefreshChan: make(chan RefreshEvent),
 		Type:        resourceType,
+		School:      school,
 	}
 }
 
+func (s *ScraperResource) logger() *slog.Logger {
+	return s.School.logger().With("resource", s.Type)
+}
+
 func (s *ScraperResource) StartHub() {
 	if s.Hub != nil {
+		s.Hub.SetLabels(s.School.ID, s.Type.String())
 		s.Hub.Start()
 	}
 }
@@ -79,6 +110,30 @@ func (s *ScraperResource) StopHub() {
 	}
 }
 
+// Records that an item of the resource has been scraped and saved
+func (s *ScraperResource) MarkScraped() {
+	s.lastScraped.Store(time.Now().UnixMilli())
+}
+
+// Returns when an item of the resource was last scraped, zero if it hasn't been since the start
+func (s *ScraperResource) LastScraped() time.Time {
+	lastScraped := s.lastScraped.Load()
+	if lastScraped == 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(lastScraped)
+}
+
+// Returns the stats of the resource's hub, zero if it isn't observed
+func (s *ScraperResource) HubStats() hub.Stats {
+	if s.Hub == nil {
+		return hub.Stats{}
+	}
+
+	return s.Hub.Stats()
+}
+
 func (s *ScraperResource) IsIndexInMetadata(index int64, metadataType MetadataType) (bool, string) {
 	s.Mu.RLock()
 	defer s.Mu.RUnlock()
@@ -200,6 +255,12 @@ func (s *ScraperResource) RemoveMetadata(index int64) {
 	}
 }
 
+func (s *ScraperResource) GetIndexes() []int64 {
+	s.Mu.RLock()
+	defer s.Mu.RUnlock()
+	return append([]int64{}, s.Indexes...)
+}
+
 func (s *ScraperResource) UpdateIndexes(indexes []int64) {
 	s.Mu.Lock()
 	defer s.Mu.Unlock()
@@ -213,11 +274,11 @@ func (s *ScraperResource) AddObserver(o *observer.Observer) {
 func (s *ScraperResource) newObserver(index int64) *observer.Observer {
 	switch s.Type {
 	case DivisionResource:
-		return newDivisionObserver(index, &s.RefreshChan)
+		return s.School.newDivisionObserver(index, &s.RefreshChan)
 	case TeacherResource:
-		return newTeacherObserver(index, &s.RefreshChan)
+		return s.School.newTeacherObserver(index, &s.RefreshChan)
 	case RoomResource:
-		return newRoomObserver(index, &s.RefreshChan)
+		return s.School.newRoomObserver(index, &s.RefreshChan)
 	}
 
 	return nil
@@ -226,11 +287,11 @@ func (s *ScraperResource) newObserver(index int64) *observer.Observer {
 func (s *ScraperResource) removeFromDatastore(index int64) error {
 	switch s.Type {
 	case DivisionResource:
-		return datastore.DeleteDivision(index)
+		return s.School.Store.DeleteDivision(index)
 	case TeacherResource:
-		return datastore.DeleteTeacher(index)
+		return s.School.Store.DeleteTeacher(index)
 	case RoomResource:
-		return datastore.DeleteRoom(index)
+		return s.School.Store.DeleteRoom(index)
 	}
 
 	return nil
@@ -238,11 +299,19 @@ func (s *ScraperResource) removeFromDatastore(index int64) error {
 
 func (s *ScraperResource) RefreshObservers() {
 	existingIndexes := make(map[int64]bool)
-	for _, index := range s.Indexes {
+	for _, index := range s.GetIndexes() {
 		existingIndexes[index] = true
 		if s.Hub.GetObserver(index) == nil {
-			fmt.Printf("adding observer for resource (%s) %d\n", s.Type, index)
+			s.logger().Debug("adding observer", "index", index)
 			observer := s.newObserver(index)
+
+			// A stored hash without the stored item would never bring it back
+			if _, _, ok := s.loadStored(index); !ok {
+				if err := s.School.Store.DeleteObserverState(observer.URL); err != nil {
+					s.logger().Error("error deleting observer state", "index", index, "error", err)
+				}
+			}
+
 			s.Hub.AddObserver(observer)
 		}
 	}
@@ -250,17 +319,18 @@ func (s *ScraperResource) RefreshObservers() {
 	observersCopy := s.Hub.GetAllObservers(true)
 	for index := range observersCopy {
 		if !existingIndexes[index] {
-			fmt.Printf("deleting observer for resource (%s) %d\n", s.Type, index)
+			s.logger().Debug("deleting observer", "index", index)
 			s.Hub.RemoveObserver(index)
 			if err := s.removeFromDatastore(index); err != nil {
-				fmt.Printf("error deleting resource (%s) from datastore: %v\n", s.Type, err)
+				s.logger().Error("error deleting from datastore", "index", index, "error", err)
 			}
 
 			s.RemoveMetadata(index)
+			s.RefreshChan <- RefreshEvent{Type: RemovedRefresh, Resource: s.Type, Index: index}
 		}
 	}
 
-	fmt.Printf("observing resource (%s) with %d observer(s)...\n", s.Type, len(s.Hub.GetAllObservers(false)))
+	s.logger().Info("observing resource", "observers", len(s.Hub.GetAllObservers(false)))
 }
 
 var (
@@ -269,15 +339,9 @@ var (
 	RoomIndexRegex     = regexp.MustCompile(`s(\d+)\.html`)
 )
 
-var (
-	DivisionsScraperResource *ScraperResource
-	TeachersScraperResource  *ScraperResource
-	RoomsScraperResource     *ScraperResource
-)
-
-func ScrapeDivision(index int64) (*models.Division, error) {
-	endpoint := makeDivisionEndpoint(index)
-	doc, err := utils.OpenDoc(Config.BaseUrl, endpoint)
+func (s *School) ScrapeDivision(index int64) (*models.Division, error) {
+	endpoint := s.makeDivisionEndpoint(index)
+	doc, err := s.openDoc(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("error opening document: %w", err)
 	}
@@ -296,20 +360,27 @@ func ScrapeDivision(index int64) (*models.Division, error) {
 	division.Designator = designator
 	division.FullName = fullName
 
-	DivisionsScraperResource.UpdateMetadata(designator, fullName, index)
+	s.Divisions.UpdateMetadata(designator, fullName, index)
 
-	schedule, err := scrapeSchedule(doc)
+	schedule, err := s.scrapeSchedule(doc)
 	if err != nil {
 		return nil, fmt.Errorf("error scraping division schedule: %w", err)
 	}
 	division.Schedule = schedule
 
+	periods, err := scrapePeriods(doc)
+	if err != nil {
+		s.Divisions.logger().Warn("error scraping periods", "index", index, "error", err)
+	} else if err := s.updatePeriods(periods); err != nil {
+		s.logger().Error("error saving periods", "error", err)
+	}
+
 	return &division, nil
 }
 
-func ScrapeTeacher(index int64) (*models.Teacher, error) {
-	endpoint := makeTeacherEndpoint(index)
-	doc, err := utils.OpenDoc(Config.BaseUrl, endpoint)
+func (s *School) ScrapeTeacher(index int64) (*models.Teacher, error) {
+	endpoint := s.makeTeacherEndpoint(index)
+	doc, err := s.openDoc(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("error opening document: %w", err)
 	}
@@ -328,9 +399,9 @@ func ScrapeTeacher(index int64) (*models.Teacher, error) {
 	teacher.Designator = designator
 	teacher.FullName = fullName
 
-	TeachersScraperResource.UpdateMetadata(designator, fullName, index)
+	s.Teachers.UpdateMetadata(designator, fullName, index)
 
-	schedule, err := scrapeSchedule(doc)
+	schedule, err := s.scrapeSchedule(doc)
 	if err != nil {
 		return nil, fmt.Errorf("error scraping division schedule: %w", err)
 	}
@@ -339,9 +410,9 @@ func ScrapeTeacher(index int64) (*models.Teacher, error) {
 	return &teacher, nil
 }
 
-func ScrapeRoom(index int64) (*models.Room, error) {
-	endpoint := makeRoomEndpoint(index)
-	doc, err := utils.OpenDoc(Config.BaseUrl, endpoint)
+func (s *School) ScrapeRoom(index int64) (*models.Room, error) {
+	endpoint := s.makeRoomEndpoint(index)
+	doc, err := s.openDoc(endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("error opening document: %w", err)
 	}
@@ -360,9 +431,9 @@ func ScrapeRoom(index int64) (*models.Room, error) {
 	room.Designator = designator
 	room.FullName = fullName
 
-	RoomsScraperResource.UpdateMetadata(designator, fullName, index)
+	s.Rooms.UpdateMetadata(designator, fullName, index)
 
-	schedule, err := scrapeSchedule(doc)
+	schedule, err := s.scrapeSchedule(doc)
 	if err != nil {
 		return nil, fmt.Errorf("error scraping division schedule: %w", err)
 	}
@@ -371,166 +442,193 @@ func ScrapeRoom(index int64) (*models.Room, error) {
 	return &room, nil
 }
 
-func ScrapeDivisionsIndexes() ([]int64, error) {
-	doc, err := utils.OpenDoc(Config.BaseUrl, Config.Endpoints.DivisionsList)
-	if err != nil {
-		return nil, fmt.Errorf("error opening document: %w", err)
-	}
+func (s *School) ScrapeDivisionsIndexes() ([]int64, error) {
+	return s.scrapeIndexes(DivisionResource, s.Config.Endpoints.DivisionsList, DivisionIndexRegex, s.Config.StaticIndexes.Divisions, s.makeDivisionEndpoint)
+}
 
-	indexes := []int64{}
-	indexes = append(indexes, Config.StaticIndexes.Divisions...)
+func (s *School) ScrapeTeachersIndexes() ([]int64, error) {
+	return s.scrapeIndexes(TeacherResource, s.Config.Endpoints.TeachersList, TeacherIndexRegex, s.Config.StaticIndexes.Teachers, s.makeTeacherEndpoint)
+}
 
-	doc.Find("a").Each(func(index int, element *goquery.Selection) {
-		href, exists := element.Attr("href")
-		if exists {
-			match := DivisionIndexRegex.FindStringSubmatch(href)
-			if len(match) > 1 {
-				number := match[1]
-				num, err := strconv.ParseInt(number, 10, 64)
-				if err != nil {
-					fmt.Printf("error converting number to uint: %v\n", err)
-					return
-				}
-				endpoint := makeDivisionEndpoint(num)
+func (s *School) ScrapeRoomsIndexes() ([]int64, error) {
+	return s.scrapeIndexes(RoomResource, s.Config.Endpoints.RoomsList, RoomIndexRegex, s.Config.StaticIndexes.Rooms, s.makeRoomEndpoint)
+}
 
-				if !utils.CheckURL(Config.BaseUrl + endpoint) {
-					fmt.Printf("error opening division " + Config.BaseUrl + endpoint + "\n")
-					return
-				}
-				indexes = append(indexes, num)
-			}
-		}
-	})
+// Sets up the schools and starts discovering them in the background, the stored
+// data is available right away, only invalid configs return an error
+func Initialize() error {
+	slog.Info("initializing scraper")
 
-	return indexes, nil
-}
+	Schools = nil
+	for _, schoolConfig := range config.Global.SchoolConfigs() {
+		school := NewSchool(schoolConfig)
+		Schools = append(Schools, school)
 
-func ScrapeTeachersIndexes() ([]int64, error) {
-	doc, err := utils.OpenDoc(Config.BaseUrl, Config.Endpoints.TeachersList)
-	if err != nil {
-		return nil, fmt.Errorf("error opening document: %w", err)
+		if err := school.Start(); err != nil {
+			return fmt.Errorf("error starting school %s: %w", school.ID, err)
+		}
 	}
 
-	indexes := []int64{}
-	indexes = append(indexes, Config.StaticIndexes.Teachers...)
+	DefaultSchool = GetSchool(config.Global.DefaultSchoolID())
 
-	doc.Find("a").Each(func(index int, element *goquery.Selection) {
-		href, exists := element.Attr("href")
-		if exists {
-			match := TeacherIndexRegex.FindStringSubmatch(href)
-			if len(match) > 1 {
-				number := match[1]
-				num, err := strconv.ParseInt(number, 10, 64)
-				if err != nil {
-					fmt.Printf("error converting number to uint: %v\n", err)
-					return
-				}
-				endpoint := makeTeacherEndpoint(num)
+	return nil
+}
 
-				if !utils.CheckURL(Config.BaseUrl + endpoint) {
-					fmt.Printf("error opening teacher " + Config.BaseUrl + endpoint + "\n")
-					return
-				}
-				indexes = append(indexes, num)
-			}
-		}
-	})
+func (s *School) Start() error {
+	s.logger().Info("starting school")
 
-	return indexes, nil
-}
+	if _, err := ParseIndexLayout(s.Config.IndexLayout); err != nil {
+		return err
+	}
 
-func ScrapeRoomsIndexes() ([]int64, error) {
-	doc, err := utils.OpenDoc(Config.BaseUrl, Config.Endpoints.RoomsList)
+	policy, err := s.newPollingPolicy()
 	if err != nil {
-		return nil, fmt.Errorf("error opening document: %w", err)
+		return fmt.Errorf("error parsing polling config: %w", err)
 	}
 
-	indexes := []int64{}
-	indexes = append(indexes, Config.StaticIndexes.Rooms...)
+	store := datastoreStateStore{store: s.Store}
+	s.Divisions.Hub = hub.NewHub(s.Config.Quantities.Workers.Division, s.Config.IgnoreCertificates, policy, store)
+	s.Rooms.Hub = hub.NewHub(s.Config.Quantities.Workers.Teacher, s.Config.IgnoreCertificates, policy, store)
+	s.Teachers.Hub = hub.NewHub(s.Config.Quantities.Workers.Room, s.Config.IgnoreCertificates, policy, store)
 
-	doc.Find("a").Each(func(index int, element *goquery.Selection) {
-		href, exists := element.Attr("href")
-		if exists {
-			match := RoomIndexRegex.FindStringSubmatch(href)
-			if len(match) > 1 {
-				number := match[1]
-				num, err := strconv.ParseInt(number, 10, 64)
-				if err != nil {
-					fmt.Printf("error converting number to uint: %v\n", err)
-					return
-				}
-				endpoint := makeRoomEndpoint(num)
-
-				if !utils.CheckURL(Config.BaseUrl + endpoint) {
-					fmt.Printf("error opening room " + Config.BaseUrl + endpoint + "\n")
-					return
-				}
-				indexes = append(indexes, num)
-			}
-		}
-	})
+	if s.Config.Endpoints.Substitutions != "" {
+		s.Substitutions.Hub = hub.NewHub(max(s.Config.Quantities.Workers.Substitution, 1), s.Config.IgnoreCertificates, policy, store)
+	} else {
+		s.Substitutions.Hub = nil
+		s.logger().Info("substitutions endpoint not configured, skipping")
+	}
 
-	return indexes, nil
-}
+	s.restoreStored()
 
-func Initialize() error {
-	fmt.Println("initializing scraper")
-	Config = config.Global.Scraper
+	go s.discover()
 
-	DivisionsScraperResource = NewScraperResource(DivisionIndexRegex, DivisionResource)
-	TeachersScraperResource = NewScraperResource(TeacherIndexRegex, TeacherResource)
-	RoomsScraperResource = NewScraperResource(RoomIndexRegex, RoomResource)
+	return nil
+}
 
-	divisionsIndexes, err := ScrapeDivisionsIndexes()
+// Scrapes the lists of all resources, the indexes are only updated if every list could be scraped
+func (s *School) scrapeAllIndexes() error {
+	divisionsIndexes, err := s.ScrapeDivisionsIndexes()
 	if err != nil {
 		return fmt.Errorf("error scraping divisions indexes: %w", err)
 	}
-	DivisionsScraperResource.Indexes = divisionsIndexes
 
-	teachersIndexes, err := ScrapeTeachersIndexes()
+	teachersIndexes, err := s.ScrapeTeachersIndexes()
 	if err != nil {
 		return fmt.Errorf("error scraping teachers indexes: %w", err)
 	}
-	TeachersScraperResource.Indexes = teachersIndexes
 
-	roomsIndexes, err := ScrapeRoomsIndexes()
+	roomsIndexes, err := s.ScrapeRoomsIndexes()
 	if err != nil {
 		return fmt.Errorf("error scraping rooms indexes: %w", err)
 	}
-	RoomsScraperResource.Indexes = roomsIndexes
+
+	s.Divisions.UpdateIndexes(divisionsIndexes)
+	s.Teachers.UpdateIndexes(teachersIndexes)
+	s.Rooms.UpdateIndexes(roomsIndexes)
+
+	s.Divisions.RestoreMetadata()
+	s.Teachers.RestoreMetadata()
+	s.Rooms.RestoreMetadata()
 
 	divisionsIndexesLength := int64(len(divisionsIndexes))
 	teachersIndexesLength := int64(len(teachersIndexes))
 	roomsIndexesLength := int64(len(roomsIndexes))
 
-	fmt.Printf("starting with %d divisions, %d teachers, %d rooms\n", divisionsIndexesLength, teachersIndexesLength, roomsIndexesLength)
+	s.logger().Info("starting", "divisions", divisionsIndexesLength, "teachers", teachersIndexesLength, "rooms", roomsIndexesLength)
 
 	if divisionsIndexesLength == 0 {
-		fmt.Printf("no divisions found despite %d workers\n", Config.Quantities.Workers.Division)
+		s.Divisions.logger().Warn("no divisions found", "workers", s.Config.Quantities.Workers.Division)
 	}
 	if teachersIndexesLength == 0 {
-		fmt.Printf("no teachers found despite %d workers\n", Config.Quantities.Workers.Teacher)
+		s.Teachers.logger().Warn("no teachers found", "workers", s.Config.Quantities.Workers.Teacher)
 	}
 	if roomsIndexesLength == 0 {
-		fmt.Printf("no rooms found despite %d workers\n", Config.Quantities.Workers.Room)
+		s.Rooms.logger().Warn("no rooms found", "workers", s.Config.Quantities.Workers.Room)
 	}
 
-	DivisionsScraperResource.Hub = hub.NewHub(Config.Quantities.Workers.Division, Config.IgnoreCertificates)
-	RoomsScraperResource.Hub = hub.NewHub(Config.Quantities.Workers.Teacher, Config.IgnoreCertificates)
-	TeachersScraperResource.Hub = hub.NewHub(Config.Quantities.Workers.Room, Config.IgnoreCertificates)
+	return nil
+}
 
-	ObserveDivisions(&DivisionsScraperResource.RefreshChan)
-	ObserveTeachers(&TeachersScraperResource.RefreshChan)
-	ObserveRooms(&RoomsScraperResource.RefreshChan)
+var (
+	discoveryRetryDelay    = 5 * time.Second
+	maxDiscoveryRetryDelay = 5 * time.Minute
+)
 
-	waitForFirstRefresh()
+// Scrapes the lists until the site responds, then observes the school
+// and marks it as ready once its pages have been checked
+func (s *School) discover() {
+	delay := discoveryRetryDelay
+	for {
+		err := s.scrapeAllIndexes()
+		if err == nil {
+			break
+		}
 
-	return nil
+		s.logger().Warn("error discovering school, retrying", "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-s.quit:
+			return
+		}
+		delay = min(delay*2, maxDiscoveryRetryDelay)
+	}
+
+	if !s.observe() {
+		return
+	}
+
+	s.waitForFirstRefresh()
+
+	s.ready.Store(true)
+	s.logger().Info("school is ready")
+}
+
+// Starts the hubs, returns false if the school has been cleaned up in the meantime
+func (s *School) observe() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.quit:
+		return false
+	default:
+	}
+
+	s.ObserveDivisions(&s.Divisions.RefreshChan)
+	s.ObserveTeachers(&s.Teachers.RefreshChan)
+	s.ObserveRooms(&s.Rooms.RefreshChan)
+
+	if s.Substitutions.Hub != nil {
+		s.ObserveSubstitutions()
+	}
+
+	return true
+}
+
+// Reports whether the school has been discovered and its pages checked
+func (s *School) Ready() bool {
+	return s.ready.Load()
+}
+
+// Reports whether the school has schedules to serve, either discovered
+// or stored by a previous run while the site can't be reached
+func (s *School) Serving() bool {
+	if s.Ready() {
+		return true
+	}
+
+	for _, resource := range []*ScraperResource{s.Divisions, s.Teachers, s.Rooms} {
+		if len(resource.GetIndexes()) > 0 {
+			return true
+		}
+	}
+
+	return false
 }
 
 func Cleanup() {
-	fmt.Println("cleaning scraper")
-	DivisionsScraperResource.StopHub()
-	TeachersScraperResource.StopHub()
-	RoomsScraperResource.StopHub()
+	slog.Info("cleaning scraper")
+	for _, school := range Schools {
+		school.Cleanup()
+	}
 }
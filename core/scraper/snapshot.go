@@ -0,0 +1,130 @@
+// scraper/snapshot.go
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/utils"
+	"smuggr.xyz/goptivum/core/source"
+)
+
+// Snapshot records the school with the given ID (the default one if it's empty)
+func Snapshot(path, schoolID string) error {
+	if schoolID == "" {
+		schoolID = config.Global.DefaultSchoolID()
+	}
+
+	for _, schoolConfig := range config.Global.SchoolConfigs() {
+		if schoolConfig.ID == schoolID {
+			return NewSchool(schoolConfig).Snapshot(path)
+		}
+	}
+
+	return fmt.Errorf("school not found: %s", schoolID)
+}
+
+// Snapshot records every page the scraper reads into a directory or a .tar.gz
+// archive, which can then be scraped by pointing the base URL at it (file://)
+func (s *School) Snapshot(path string) error {
+	s.logger().Info("recording snapshot", "path", path)
+
+	lists := []struct {
+		endpoint string
+		regex    *regexp.Regexp
+	}{
+		{s.Config.Endpoints.DivisionsList, DivisionIndexRegex},
+		{s.Config.Endpoints.TeachersList, TeacherIndexRegex},
+		{s.Config.Endpoints.RoomsList, RoomIndexRegex},
+	}
+
+	var endpoints []string
+	for _, list := range lists {
+		endpoints = append(endpoints, list.endpoint)
+
+		// The framed pages the indexes are found in have to be recorded too
+		doc, err := s.openDoc(list.endpoint)
+		if err != nil {
+			return fmt.Errorf("error opening list %s: %w", list.endpoint, err)
+		}
+		_, frames := s.findListIndexes(doc, list.endpoint, list.regex)
+		endpoints = append(endpoints, frames...)
+	}
+	if s.Config.Endpoints.Substitutions != "" {
+		endpoints = append(endpoints, s.Config.Endpoints.Substitutions)
+	}
+
+	divisionsIndexes, err := s.ScrapeDivisionsIndexes()
+	if err != nil {
+		return fmt.Errorf("error scraping divisions indexes: %w", err)
+	}
+	for _, index := range divisionsIndexes {
+		endpoints = append(endpoints, s.makeDivisionEndpoint(index))
+	}
+
+	teachersIndexes, err := s.ScrapeTeachersIndexes()
+	if err != nil {
+		return fmt.Errorf("error scraping teachers indexes: %w", err)
+	}
+	for _, index := range teachersIndexes {
+		endpoints = append(endpoints, s.makeTeacherEndpoint(index))
+	}
+
+	roomsIndexes, err := s.ScrapeRoomsIndexes()
+	if err != nil {
+		return fmt.Errorf("error scraping rooms indexes: %w", err)
+	}
+	for _, index := range roomsIndexes {
+		endpoints = append(endpoints, s.makeRoomEndpoint(index))
+	}
+
+	// Mirrors are served as plain files, so query strings can't be kept,
+	// endpoints differing only in them would overwrite each other
+	names := make(map[string]string)
+	var unique []string
+	for _, endpoint := range endpoints {
+		name, _, _ := strings.Cut(endpoint, "?")
+		if recorded, ok := names[name]; ok {
+			if recorded != endpoint {
+				return fmt.Errorf("endpoints %s and %s would both be recorded as %s", recorded, endpoint, name)
+			}
+			continue
+		}
+		names[name] = endpoint
+		unique = append(unique, endpoint)
+	}
+	endpoints = unique
+
+	writer, err := source.NewSnapshotWriter(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	recorded := 0
+	for _, endpoint := range endpoints {
+		body, err := utils.Fetch(s.Client, s.Config.BaseUrl, endpoint)
+		if err != nil {
+			s.logger().Warn("error recording page", "endpoint", endpoint, "error", err)
+			continue
+		}
+
+		name, _, _ := strings.Cut(endpoint, "?")
+		if err := writer.Add(name, body, now); err != nil {
+			writer.Close()
+			return fmt.Errorf("error writing %s into snapshot: %w", name, err)
+		}
+		recorded++
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error closing snapshot: %w", err)
+	}
+
+	s.logger().Info("recorded snapshot", "path", path, "recorded", recorded, "pages", len(endpoints))
+
+	return nil
+}
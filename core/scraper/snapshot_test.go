@@ -0,0 +1,74 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/core/scraper/optivumtest"
+)
+
+func TestSnapshotFramesLayout(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+	server.SetListLayout(optivumtest.FramesList)
+
+	school := setupScraper(t, server)
+	school.Config.IndexLayout = FramesLayout.String()
+
+	path := t.TempDir()
+	if err := school.Snapshot(path); err != nil {
+		t.Fatalf("error recording snapshot: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(path, optivumtest.FramedListEndpoint)); err != nil {
+		t.Errorf("framed list wasn't recorded: %v", err)
+	}
+
+	// The school can be scraped back from its own snapshot
+	mirror := NewSchool(config.SchoolConfig{ID: "mirror", Scraper: school.Config})
+	mirror.Config.BaseUrl = "file://" + path + "/"
+
+	tests := []struct {
+		name   string
+		scrape func() ([]int64, error)
+	}{
+		{"divisions", mirror.ScrapeDivisionsIndexes},
+		{"teachers", mirror.ScrapeTeachersIndexes},
+		{"rooms", mirror.ScrapeRoomsIndexes},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexes, err := test.scrape()
+			if err != nil {
+				t.Fatalf("error scraping indexes: %v", err)
+			}
+			if !slices.Equal(indexes, []int64{1, 2}) {
+				t.Errorf("got indexes %v, want [1 2]", indexes)
+			}
+		})
+	}
+}
+
+func TestSnapshotQueryCollision(t *testing.T) {
+	server := optivumtest.NewServer(newTestSchool())
+	defer server.Close()
+
+	school := setupScraper(t, server)
+	school.Config.Endpoints.DivisionsList = optivumtest.ListEndpoint + "?oddzialy"
+	school.Config.Endpoints.TeachersList = optivumtest.ListEndpoint + "?nauczyciele"
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	err := school.Snapshot(path)
+	if err == nil || !strings.Contains(err.Error(), "would both be recorded") {
+		t.Fatalf("got error %v, want a collision", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("snapshot was written despite the collision")
+	}
+}
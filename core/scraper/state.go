@@ -0,0 +1,95 @@
+// scraper/state.go
+package scraper
+
+import (
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/datastore"
+	"smuggr.xyz/goptivum/core/observer"
+)
+
+// Keeps the observer states in the datastore, so restarts don't re-scrape unchanged pages
+type datastoreStateStore struct {
+	store *datastore.Store
+}
+
+func (s datastoreStateStore) LoadState(url string) (*observer.State, error) {
+	state, err := s.store.GetObserverState(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &observer.State{
+		Hash:         state.Hash,
+		ETag:         state.Etag,
+		LastModified: state.LastModified,
+		LastChecked:  time.UnixMilli(state.LastChecked),
+	}, nil
+}
+
+func (s datastoreStateStore) SaveState(url string, state *observer.State) error {
+	var lastChecked int64
+	if !state.LastChecked.IsZero() {
+		lastChecked = state.LastChecked.UnixMilli()
+	}
+
+	return s.store.SetObserverState(url, &models.ObserverState{
+		Hash:         state.Hash,
+		Etag:         state.ETag,
+		LastModified: state.LastModified,
+		LastChecked:  lastChecked,
+	})
+}
+
+func (s datastoreStateStore) DeleteState(url string) error {
+	return s.store.DeleteObserverState(url)
+}
+
+// Returns the designator and full name of the stored item, ok is false if it isn't stored
+func (s *ScraperResource) loadStored(index int64) (designator string, fullName string, ok bool) {
+	switch s.Type {
+	case DivisionResource:
+		if division, err := s.School.Store.GetDivision(index); err == nil {
+			return division.Designator, division.FullName, true
+		}
+	case TeacherResource:
+		if teacher, err := s.School.Store.GetTeacher(index); err == nil {
+			return teacher.Designator, teacher.FullName, true
+		}
+	case RoomResource:
+		if room, err := s.School.Store.GetRoom(index); err == nil {
+			return room.Designator, room.FullName, true
+		}
+	}
+
+	return "", "", false
+}
+
+// Fills the metadata from the stored items, which aren't re-scraped
+// on startup unless their pages changed
+func (s *ScraperResource) RestoreMetadata() {
+	restored := 0
+	for _, index := range s.GetIndexes() {
+		if designator, fullName, ok := s.loadStored(index); ok {
+			s.UpdateMetadata(designator, fullName, index)
+			restored++
+		}
+	}
+
+	s.logger().Info("restored metadata", "restored", restored)
+}
+
+// Serves the items stored by the previous run until the lists are scraped again
+func (s *School) restoreStored() {
+	for _, resource := range []*ScraperResource{s.Divisions, s.Teachers, s.Rooms} {
+		indexes, err := s.Store.GetIndexes(resource.Type.String())
+		if err != nil {
+			resource.logger().Error("error restoring indexes", "error", err)
+			continue
+		}
+
+		resource.UpdateIndexes(indexes)
+		resource.RestoreMetadata()
+	}
+}
@@ -0,0 +1,226 @@
+// scraper/substitutions.go
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+	"smuggr.xyz/goptivum/core/observer"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const SubstitutionsDateLayout = "2006-01-02"
+
+var (
+	isoDateRegex    = regexp.MustCompile(`(\d{4})-(\d{1,2})-(\d{1,2})`)
+	dottedDateRegex = regexp.MustCompile(`(\d{1,2})\.(\d{1,2})\.(\d{4})`)
+)
+
+// Collapses whitespace (including &nbsp;) into single spaces
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Finds the date of the substitutions in the page header,
+// Optivum uses either "2024-10-14" or "14.10.2024"
+func parseSubstitutionsDate(s string) (string, error) {
+	var parts [3]string
+	if match := isoDateRegex.FindStringSubmatch(s); len(match) > 3 {
+		parts = [3]string{match[1], match[2], match[3]}
+	} else if match := dottedDateRegex.FindStringSubmatch(s); len(match) > 3 {
+		parts = [3]string{match[3], match[2], match[1]}
+	} else {
+		return "", fmt.Errorf("no date found in: %s", s)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return "", fmt.Errorf("invalid date: %s %v", s, err)
+		}
+		numbers[i] = number
+	}
+
+	date, err := time.Parse(SubstitutionsDateLayout, fmt.Sprintf("%04d-%02d-%02d", numbers[0], numbers[1], numbers[2]))
+	if err != nil {
+		return "", fmt.Errorf("invalid date: %w", err)
+	}
+
+	return date.Format(SubstitutionsDateLayout), nil
+}
+
+// Resolves an index by designator or full name, returns 0 if not found
+func resolveIndex(resource *ScraperResource, name string) int64 {
+	if resource == nil || name == "" {
+		return 0
+	}
+
+	if duplicates := resource.GetIndexFromDesignator(name); duplicates != nil && len(duplicates.Values) > 0 {
+		return duplicates.Values[0]
+	}
+
+	if duplicates := resource.GetIndexFromFullName(name); duplicates != nil && len(duplicates.Values) > 0 {
+		return duplicates.Values[0]
+	}
+
+	return 0
+}
+
+// Splits the lesson description, e.g. "3TI-1/2 - matematyka, 105" into
+// the division designator, subject and room designator
+func splitSubstitutionDescription(s string) (string, string, string) {
+	var division, subject, room string
+
+	parts := strings.SplitN(s, " - ", 2)
+	if len(parts) == 2 {
+		division, _ = splitLessonGroup(parts[0])
+		s = parts[1]
+	}
+
+	parts = strings.SplitN(s, ",", 2)
+	subject = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		room = strings.TrimSpace(parts[1])
+		room = strings.TrimSpace(strings.TrimPrefix(room, "s."))
+	}
+
+	return strings.TrimSpace(division), subject, room
+}
+
+func (s *School) parseSubstitution(cells *goquery.Selection, absentTeacher string) (*models.Substitution, error) {
+	period, err := strconv.ParseInt(cleanText(cells.Eq(0).Text()), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period: %w", err)
+	}
+
+	division, subject, room := splitSubstitutionDescription(cleanText(cells.Eq(1).Text()))
+	replacementTeacher := cleanText(cells.Eq(2).Text())
+
+	substitution := &models.Substitution{
+		Period:                  period,
+		AbsentTeacher:           absentTeacher,
+		AbsentTeacherIndex:      resolveIndex(s.Teachers, absentTeacher),
+		ReplacementTeacher:      replacementTeacher,
+		ReplacementTeacherIndex: resolveIndex(s.Teachers, replacementTeacher),
+		RoomDesignator:          room,
+		RoomIndex:               resolveIndex(s.Rooms, room),
+		DivisionDesignator:      division,
+		DivisionIndex:           resolveIndex(s.Divisions, division),
+		Subject:                 subject,
+	}
+
+	if cells.Length() > 3 {
+		substitution.Note = cleanText(cells.Eq(3).Text())
+	}
+
+	return substitution, nil
+}
+
+// Scrapes the substitutions page generated by Optivum, rows spanning the whole
+// table are headers (first the date, then the absent teachers) and the
+// remaining rows are "period | description | replacement | note" entries.
+// Pages without a date in the header or the title are rejected
+func (s *School) scrapeSubstitutions(doc *goquery.Document) (*models.Substitutions, error) {
+	substitutions := &models.Substitutions{}
+	absentTeacher := ""
+
+	doc.Find("table tr").Each(func(i int, rowElement *goquery.Selection) {
+		cells := rowElement.Children().Filter("td")
+		if cells.Length() == 0 {
+			return
+		}
+
+		if cells.Length() == 1 {
+			text := cleanText(cells.Text())
+			if text == "" {
+				return
+			}
+
+			if substitutions.Date == "" {
+				if date, err := parseSubstitutionsDate(text); err == nil {
+					substitutions.Date = date
+					return
+				}
+			}
+
+			absentTeacher = text
+			return
+		}
+
+		if cells.Length() < 3 {
+			return
+		}
+
+		// Column headers (lekcja, opis, zastępca, uwagi) don't start with a number
+		substitution, err := s.parseSubstitution(cells, absentTeacher)
+		if err != nil {
+			return
+		}
+		substitutions.Substitutions = append(substitutions.Substitutions, substitution)
+	})
+
+	// Substitutions are often published the day before, so a page
+	// without a date can't be assumed to be today's
+	if substitutions.Date == "" {
+		date, err := parseSubstitutionsDate(doc.Find("title").Text())
+		if err != nil {
+			return nil, fmt.Errorf("error finding substitutions date: %w", err)
+		}
+		substitutions.Date = date
+	}
+
+	return substitutions, nil
+}
+
+func (s *School) ScrapeSubstitutions() (*models.Substitutions, error) {
+	doc, err := s.openDoc(s.Config.Endpoints.Substitutions)
+	if err != nil {
+		return nil, fmt.Errorf("error opening document: %w", err)
+	}
+
+	substitutions, err := s.scrapeSubstitutions(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error scraping substitutions: %w", err)
+	}
+
+	return substitutions, nil
+}
+
+func (s *School) ObserveSubstitutions() {
+	s.Substitutions.logger().Info("observing substitutions")
+
+	extractFunc := func(doc *goquery.Document) string {
+		var content []string
+		doc.Find("table td").Each(func(i int, s *goquery.Selection) {
+			content = append(content, s.Text())
+		})
+		return strings.Join(content, " ")
+	}
+
+	callbackFunc := func() error {
+		s.Substitutions.logger().Info("substitutions changed")
+		substitutions, err := s.ScrapeSubstitutions()
+		if err != nil {
+			return err
+		}
+
+		if err := s.Store.SetSubstitutions(substitutions); err != nil {
+			return fmt.Errorf("error saving substitutions: %w", err)
+		}
+
+		s.Substitutions.MarkScraped()
+		s.Substitutions.logger().Info("saved substitutions", "date", substitutions.Date, "substitutions", len(substitutions.Substitutions))
+		return nil
+	}
+
+	s.Substitutions.StartHub()
+
+	s.Substitutions.Observer = observer.NewObserver(0, s.Config.BaseUrl+s.Config.Endpoints.Substitutions, s.observerInterval(0), extractFunc, callbackFunc)
+	s.Substitutions.AddObserver(s.Substitutions.Observer)
+}
@@ -0,0 +1,137 @@
+package scraper
+
+import (
+	"testing"
+
+	"smuggr.xyz/goptivum/common/config"
+	"smuggr.xyz/goptivum/common/models"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Trimmed down substitutions page as exported by Optivum
+const substitutionsPage = `<html>
+<head><title>Zastępstwa w dniu 14.10.2024</title></head>
+<body>
+<table border="1" cellspacing="0" cellpadding="4">
+<tr><td colspan="4" class="st0" nowrap>Zastępstwa w dniu 2024-10-14 (poniedziałek)</td></tr>
+<tr><td colspan="4" class="st1">&nbsp;</td></tr>
+<tr><td colspan="4" class="st1" nowrap>J.Kowalski</td></tr>
+<tr><td class="st4">lekcja</td><td class="st4">opis</td><td class="st4">zastępca</td><td class="st4">uwagi</td></tr>
+<tr><td class="st5">1</td><td class="st6">3TI-1/2 - matematyka, s.105</td><td class="st7">A.Nowak</td><td class="st8">&nbsp;</td></tr>
+<tr><td class="st5">2</td><td class="st6">1A - fizyka, 101</td><td class="st7">Uczniowie&nbsp;przychodzą później</td><td class="st8">&nbsp;zajęcia&nbsp;odwołane </td></tr>
+<tr><td colspan="4" class="st1" nowrap>P.Wiśniewski</td></tr>
+<tr><td class="st4">lekcja</td><td class="st4">opis</td><td class="st4">zastępca</td><td class="st4">uwagi</td></tr>
+<tr><td class="st5">7</td><td class="st6">informatyka, 204</td><td class="st7">J.Kowalski</td></tr>
+</table>
+</body>
+</html>`
+
+func TestParseSubstitutionsDate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Zastępstwa w dniu 2024-10-14 (poniedziałek)", "2024-10-14"},
+		{"Zastępstwa w dniu 14.10.2024", "2024-10-14"},
+		{"Zastępstwa 2024-1-7", "2024-01-07"},
+		{"zastępstwa 7.1.2025 r.", "2025-01-07"},
+		{"Zastępstwa w dniu 2024-02-30", ""},
+		{"Zastępstwa w dniu 31.13.2024", ""},
+		{"Zastępstwa na jutro", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			date, err := parseSubstitutionsDate(test.input)
+			if test.want == "" {
+				if err == nil {
+					t.Errorf("date: got %q, want an error", date)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error parsing date: %v", err)
+			}
+			if date != test.want {
+				t.Errorf("date: got %q, want %q", date, test.want)
+			}
+		})
+	}
+}
+
+func TestScrapeSubstitutions(t *testing.T) {
+	school := NewSchool(config.SchoolConfig{ID: "test"})
+	school.Teachers.UpdateMetadata("JK", "J.Kowalski", 1)
+	school.Teachers.UpdateMetadata("AN", "A.Nowak", 2)
+	school.Divisions.UpdateMetadata("3TI", "technik informatyk", 3)
+	school.Rooms.UpdateMetadata("105", "informatyczna", 5)
+
+	substitutions, err := school.scrapeSubstitutions(parsePage(t, substitutionsPage))
+	if err != nil {
+		t.Fatalf("error scraping substitutions: %v", err)
+	}
+
+	if substitutions.Date != "2024-10-14" {
+		t.Errorf("date: got %q, want \"2024-10-14\"", substitutions.Date)
+	}
+
+	want := []*models.Substitution{
+		{
+			Period: 1, AbsentTeacher: "J.Kowalski", AbsentTeacherIndex: 1,
+			ReplacementTeacher: "A.Nowak", ReplacementTeacherIndex: 2,
+			DivisionDesignator: "3TI", DivisionIndex: 3,
+			Subject: "matematyka", RoomDesignator: "105", RoomIndex: 5,
+		},
+		{
+			Period: 2, AbsentTeacher: "J.Kowalski", AbsentTeacherIndex: 1,
+			ReplacementTeacher: "Uczniowie przychodzą później",
+			DivisionDesignator: "1A", Subject: "fizyka", RoomDesignator: "101",
+			Note: "zajęcia odwołane",
+		},
+		{
+			Period: 7, AbsentTeacher: "P.Wiśniewski",
+			ReplacementTeacher: "J.Kowalski", ReplacementTeacherIndex: 1,
+			Subject: "informatyka", RoomDesignator: "204",
+		},
+	}
+
+	if len(substitutions.Substitutions) != len(want) {
+		t.Fatalf("substitutions: got %d, want %d", len(substitutions.Substitutions), len(want))
+	}
+	for i, substitution := range substitutions.Substitutions {
+		if !proto.Equal(substitution, want[i]) {
+			t.Errorf("substitution %d: got %v, want %v", i, substitution, want[i])
+		}
+	}
+}
+
+func TestScrapeSubstitutionsDateFromTitle(t *testing.T) {
+	school := NewSchool(config.SchoolConfig{ID: "test"})
+
+	page := `<html><head><title>Zastępstwa 15.10.2024</title></head><body><table>` +
+		`<tr><td colspan="4">J.Kowalski</td></tr>` +
+		`<tr><td>3</td><td>1A - fizyka, 101</td><td>A.Nowak</td></tr>` +
+		`</table></body></html>`
+
+	substitutions, err := school.scrapeSubstitutions(parsePage(t, page))
+	if err != nil {
+		t.Fatalf("error scraping substitutions: %v", err)
+	}
+	if substitutions.Date != "2024-10-15" {
+		t.Errorf("date: got %q, want \"2024-10-15\"", substitutions.Date)
+	}
+	if len(substitutions.Substitutions) != 1 || substitutions.Substitutions[0].AbsentTeacher != "J.Kowalski" {
+		t.Errorf("substitutions: got %v, want a single one of J.Kowalski", substitutions.Substitutions)
+	}
+
+	// Pages without a date aren't stored under any day
+	page = `<html><head><title>Zastępstwa</title></head><body><table>` +
+		`<tr><td colspan="4">J.Kowalski</td></tr>` +
+		`<tr><td>3</td><td>1A - fizyka, 101</td><td>A.Nowak</td></tr>` +
+		`</table></body></html>`
+	if substitutions, err := school.scrapeSubstitutions(parsePage(t, page)); err == nil {
+		t.Errorf("substitutions without a date: got %v, want an error", substitutions)
+	}
+}
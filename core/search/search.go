@@ -0,0 +1,295 @@
+// search/search.go
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+type Field string
+
+const (
+	DesignatorField Field = "designator"
+	FullNameField   Field = "full_name"
+	SubjectField    Field = "subject"
+)
+
+func (f Field) String() string {
+	return string(f)
+}
+
+// Matches on designators and names rank above matches on taught subjects
+var fieldWeights = map[Field]float64{
+	DesignatorField: 1.0,
+	FullNameField:   0.9,
+	SubjectField:    0.6,
+}
+
+// Document is a single searchable division, teacher or room
+type Document struct {
+	Type       string
+	Index      int64
+	Designator string
+	FullName   string
+	Subjects   []string
+}
+
+type term struct {
+	Field Field
+	Text  string
+	Whole string
+	Words []string
+}
+
+type indexedDocument struct {
+	Document *Document
+	Terms    []*term
+}
+
+// Refreshes mark the index dirty in bursts while a school is scraped,
+// it's rebuilt at most this often
+const defaultRebuildDelay = 5 * time.Second
+
+// Index is built from the loader on the first search. Once it's marked dirty
+// the searches keep using the current documents while it's rebuilt in the
+// background, so they never wait for the loader
+type Index struct {
+	mu           sync.RWMutex
+	dirty        bool
+	built        bool
+	scheduled    bool
+	lastRebuild  time.Time
+	rebuildDelay time.Duration
+	loader       func() []*Document
+	documents    []*indexedDocument
+
+	// Serializes the rebuilds, the loader runs without holding mu
+	rebuildMu sync.Mutex
+}
+
+func NewIndex(loader func() []*Document) *Index {
+	return &Index{
+		dirty:        true,
+		rebuildDelay: defaultRebuildDelay,
+		loader:       loader,
+	}
+}
+
+// MarkDirty makes the next search rebuild the index in the background
+func (i *Index) MarkDirty() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dirty = true
+}
+
+func newTerm(field Field, text string) *term {
+	return &term{
+		Field: field,
+		Text:  text,
+		Whole: strings.Join(Tokenize(text), ""),
+		Words: Tokenize(text),
+	}
+}
+
+// Loads the documents again unless the index is up to date, the
+// refreshes marking it dirty during the load trigger another rebuild
+func (i *Index) rebuild() {
+	i.rebuildMu.Lock()
+	defer i.rebuildMu.Unlock()
+
+	i.mu.Lock()
+	if i.built && !i.dirty {
+		i.mu.Unlock()
+		return
+	}
+	i.dirty = false
+	i.mu.Unlock()
+
+	indexed := indexDocuments(i.loader())
+
+	i.mu.Lock()
+	i.documents = indexed
+	i.built = true
+	i.lastRebuild = time.Now()
+	i.mu.Unlock()
+}
+
+// Rebuilds the index in the background, waiting for the rebuild delay
+// since the last one so a burst of refreshes causes a single rebuild
+func (i *Index) scheduleRebuild() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.scheduled {
+		return
+	}
+	i.scheduled = true
+
+	delay := max(time.Until(i.lastRebuild.Add(i.rebuildDelay)), 0)
+	time.AfterFunc(delay, func() {
+		i.rebuild()
+
+		i.mu.Lock()
+		i.scheduled = false
+		dirty := i.dirty
+		i.mu.Unlock()
+
+		if dirty {
+			i.scheduleRebuild()
+		}
+	})
+}
+
+// Returns the documents to search, only the first searches wait for them to be loaded
+func (i *Index) current() []*indexedDocument {
+	i.mu.RLock()
+	documents, built, dirty := i.documents, i.built, i.dirty
+	i.mu.RUnlock()
+
+	if !built {
+		i.rebuild()
+
+		i.mu.RLock()
+		defer i.mu.RUnlock()
+		return i.documents
+	}
+
+	if dirty {
+		i.scheduleRebuild()
+	}
+
+	return documents
+}
+
+func indexDocuments(documents []*Document) []*indexedDocument {
+	indexed := make([]*indexedDocument, 0, len(documents))
+
+	for _, document := range documents {
+		terms := []*term{
+			newTerm(DesignatorField, document.Designator),
+			newTerm(FullNameField, document.FullName),
+		}
+		for _, subject := range document.Subjects {
+			terms = append(terms, newTerm(SubjectField, subject))
+		}
+
+		indexed = append(indexed, &indexedDocument{
+			Document: document,
+			Terms:    terms,
+		})
+	}
+
+	return indexed
+}
+
+// Scores how well the query word matches the word, 0 means no match
+func scoreWord(query, word string) float64 {
+	switch {
+	case query == word:
+		return 1.0
+	case strings.HasPrefix(word, query):
+		return 0.9
+	case strings.Contains(word, query):
+		return 0.7
+	}
+
+	typos := maxTypos(len([]rune(query)))
+	if typos == 0 {
+		return 0
+	}
+
+	distance := levenshtein(query, word)
+	// Words that are still being typed
+	if wordRunes := []rune(word); len(wordRunes) > len([]rune(query)) {
+		distance = min(distance, levenshtein(query, string(wordRunes[:len([]rune(query))])))
+	}
+
+	if distance > typos {
+		return 0
+	}
+
+	return 0.6 - 0.1*float64(distance)
+}
+
+// Every query word has to match some word of the term
+func scoreTerm(queryWords []string, queryWhole string, t *term) float64 {
+	if len(t.Words) == 0 {
+		return 0
+	}
+
+	// Designators like "3 TI" are often typed without spaces
+	if queryWhole == t.Whole {
+		return 1.0
+	}
+
+	var total float64
+	for _, queryWord := range queryWords {
+		var best float64
+		for _, word := range t.Words {
+			best = max(best, scoreWord(queryWord, word))
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	return total / float64(len(queryWords))
+}
+
+// Search returns at most limit documents matching the query, best matches first
+func (i *Index) Search(query string, limit int) []*models.SearchResult {
+	documents := i.current()
+
+	queryWords := Tokenize(query)
+	if len(queryWords) == 0 {
+		return nil
+	}
+	queryWhole := strings.Join(queryWords, "")
+
+	var results []*models.SearchResult
+	for _, document := range documents {
+		var best *models.SearchResult
+		for _, t := range document.Terms {
+			score := scoreTerm(queryWords, queryWhole, t) * fieldWeights[t.Field]
+			if score == 0 || (best != nil && score <= best.Score) {
+				continue
+			}
+
+			best = &models.SearchResult{
+				Type:       document.Document.Type,
+				Index:      document.Document.Index,
+				Designator: document.Document.Designator,
+				FullName:   document.Document.FullName,
+				Field:      t.Field.String(),
+				Matched:    t.Text,
+				Score:      score,
+			}
+		}
+
+		if best != nil {
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		if results[a].Type != results[b].Type {
+			return results[a].Type < results[b].Type
+		}
+		return results[a].Designator < results[b].Designator
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
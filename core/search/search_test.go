@@ -0,0 +1,170 @@
+// search/search_test.go
+package search
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Łódź":            "lodz",
+		"ZAŻÓŁĆ GĘŚLĄ":    "zazolc gesla",
+		"Język polski":    "jezyk polski",
+		"3 TI":            "3 ti",
+		"already plain 1": "already plain 1",
+	}
+
+	for input, expected := range tests {
+		if got := Normalize(input); got != expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"Język polski":        {"jezyk", "polski"},
+		"3 TI":                {"3", "ti"},
+		"mat.-fiz. (gr. 1/2)": {"mat", "fiz", "gr", "1", "2"},
+		"  ":                  {},
+	}
+
+	for input, expected := range tests {
+		if got := Tokenize(input); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Tokenize(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestScoreWord(t *testing.T) {
+	tests := []struct {
+		query, word string
+		expected    float64
+	}{
+		{"fizyka", "fizyka", 1.0},
+		{"fiz", "fizyka", 0.9},
+		{"zyk", "fizyka", 0.7},
+		{"fizyka", "fizika", 0.5},
+		{"matematka", "matematyka", 0.5},
+		{"infr", "informatyka", 0.5},
+		{"abc", "abd", 0},
+		{"chemia", "fizyka", 0},
+	}
+
+	for _, tt := range tests {
+		if got := scoreWord(tt.query, tt.word); got != tt.expected {
+			t.Errorf("scoreWord(%q, %q) = %v, expected %v", tt.query, tt.word, got, tt.expected)
+		}
+	}
+}
+
+func TestScoreTerm(t *testing.T) {
+	tests := []struct {
+		query    string
+		text     string
+		expected float64
+	}{
+		{"3ti", "3 TI", 1.0},
+		{"3 ti", "3 TI", 1.0},
+		{"jan kow", "Jan Kowalski", 0.95},
+		{"kowalski jan", "Jan Kowalski", 1.0},
+		{"jan nowak", "Jan Kowalski", 0},
+		{"jan", "", 0},
+	}
+
+	for _, tt := range tests {
+		words := Tokenize(tt.query)
+		if got := scoreTerm(words, strings.Join(words, ""), newTerm(FullNameField, tt.text)); got != tt.expected {
+			t.Errorf("scoreTerm(%q, %q) = %v, expected %v", tt.query, tt.text, got, tt.expected)
+		}
+	}
+}
+
+func testDocuments() []*Document {
+	return []*Document{
+		{Type: "division", Index: 1, Designator: "3 TI", FullName: "3 TI technik informatyk", Subjects: []string{"matematyka", "informatyka"}},
+		{Type: "teacher", Index: 2, Designator: "JK", FullName: "Jan Kowalski", Subjects: []string{"fizyka"}},
+		{Type: "teacher", Index: 3, Designator: "AN", FullName: "Anna Nowak", Subjects: []string{"informatyka"}},
+		{Type: "room", Index: 4, Designator: "12", FullName: "12 pracownia informatyczna"},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	index := NewIndex(testDocuments)
+
+	results := index.Search("3ti", 0)
+	if len(results) != 1 || results[0].Index != 1 || results[0].Field != DesignatorField.String() {
+		t.Fatalf("expected the division designator to match, got %+v", results)
+	}
+
+	results = index.Search("informatyk", 0)
+	var indexes []int64
+	for _, result := range results {
+		indexes = append(indexes, result.Index)
+	}
+	// A prefix of the subject ranks above a typo in the room's name
+	if expected := []int64{1, 3, 4}; !reflect.DeepEqual(indexes, expected) {
+		t.Fatalf("expected results %v, got %v", expected, indexes)
+	}
+	if results[1].Field != SubjectField.String() || results[1].Matched != "informatyka" {
+		t.Errorf("expected the teacher to match on the subject, got %+v", results[1])
+	}
+
+	if results := index.Search("informatyk", 2); len(results) != 2 {
+		t.Errorf("expected the limit to cut the results to 2, got %d", len(results))
+	}
+	if results := index.Search("kowlaski", 0); len(results) != 1 || results[0].Index != 2 {
+		t.Errorf("expected a typo to still match the teacher, got %+v", results)
+	}
+	if results := index.Search(" - ", 0); results != nil {
+		t.Errorf("expected no results for an empty query, got %+v", results)
+	}
+}
+
+func TestRebuildOutsideLock(t *testing.T) {
+	var index *Index
+	var loads atomic.Int32
+	documents := testDocuments()[:1]
+
+	index = NewIndex(func() []*Document {
+		loads.Add(1)
+		// The searches and refreshes mustn't wait for the loader
+		if !index.mu.TryLock() {
+			t.Error("loader called while holding the index lock")
+		} else {
+			index.mu.Unlock()
+		}
+		return documents
+	})
+	index.rebuildDelay = 20 * time.Millisecond
+
+	if results := index.Search("jan", 0); len(results) != 0 {
+		t.Fatalf("expected no results before the teacher is added, got %+v", results)
+	}
+
+	documents = testDocuments()
+	index.MarkDirty()
+	index.MarkDirty()
+
+	// Served from the current documents while the rebuild is pending
+	if results := index.Search("jan", 0); len(results) != 0 {
+		t.Fatalf("expected the stale index to be searched, got %+v", results)
+	}
+	index.Search("jan", 0)
+
+	deadline := time.Now().Add(time.Second)
+	for len(index.Search("jan", 0)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the index to be rebuilt in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := loads.Load(); got != 2 {
+		t.Errorf("expected a single background rebuild, the loader ran %d times", got)
+	}
+}
@@ -0,0 +1,113 @@
+// source/archive.go
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// archiveFS is a read-only, in-memory file system holding the files of a tarball
+type archiveFS struct {
+	files   map[string]*archiveEntry
+	modTime time.Time
+}
+
+type archiveEntry struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+type archiveFile struct {
+	*bytes.Reader
+	entry *archiveEntry
+}
+
+func (e *archiveEntry) Name() string       { return path.Base(e.name) }
+func (e *archiveEntry) Size() int64        { return int64(len(e.data)) }
+func (e *archiveEntry) Mode() fs.FileMode  { return 0o444 }
+func (e *archiveEntry) ModTime() time.Time { return e.modTime }
+func (e *archiveEntry) IsDir() bool        { return false }
+func (e *archiveEntry) Sys() any           { return nil }
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) { return f.entry, nil }
+func (f *archiveFile) Close() error               { return nil }
+
+func (a *archiveFS) Open(name string) (fs.File, error) {
+	entry, ok := a.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &archiveFile{Reader: bytes.NewReader(entry.data), entry: entry}, nil
+}
+
+func isArchive(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// Normalizes the name of an archived file into a valid fs.FS path
+func cleanArchivePath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func loadArchive(archivePath string) (*archiveFS, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening archive: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive info: %w", err)
+	}
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	archive := &archiveFS{
+		files:   make(map[string]*archiveEntry),
+		modTime: info.ModTime(),
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from archive: %w", header.Name, err)
+		}
+
+		name := cleanArchivePath(header.Name)
+		archive.files[name] = &archiveEntry{
+			name:    name,
+			data:    data,
+			modTime: header.ModTime,
+		}
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,93 @@
+// source/snapshot.go
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotWriter records pages into a mirror that can be used as a base URL later on
+type SnapshotWriter interface {
+	Add(name string, data []byte, modTime time.Time) error
+	Close() error
+}
+
+type directoryWriter struct {
+	root string
+}
+
+type archiveWriter struct {
+	file       *os.File
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+}
+
+// Writes into a .tar.gz archive if the path ends with it, otherwise into a directory
+func NewSnapshotWriter(path string) (SnapshotWriter, error) {
+	if !isArchive(filepath.Base(path)) {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating snapshot directory: %w", err)
+		}
+		return &directoryWriter{root: path}, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot archive: %w", err)
+	}
+
+	gzipWriter := gzip.NewWriter(file)
+	return &archiveWriter{
+		file:       file,
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+	}, nil
+}
+
+func (w *directoryWriter) Add(name string, data []byte, modTime time.Time) error {
+	target := filepath.Join(w.root, filepath.FromSlash(cleanArchivePath(name)))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Chtimes(target, modTime, modTime)
+}
+
+func (w *directoryWriter) Close() error {
+	return nil
+}
+
+func (w *archiveWriter) Add(name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:     cleanArchivePath(name),
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+
+	if err := w.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := w.tarWriter.Write(data)
+	return err
+}
+
+func (w *archiveWriter) Close() error {
+	if err := w.tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := w.gzipWriter.Close(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
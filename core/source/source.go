@@ -0,0 +1,97 @@
+// source/source.go
+package source
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"smuggr.xyz/goptivum/common/utils"
+)
+
+// Transport serves file:// URLs from the disk, including files inside
+// .tar.gz snapshots (file:///snapshots/school.tar.gz/plany/o1.html), and
+// passes every other URL on to the wrapped transport
+type Transport struct {
+	next     http.RoundTripper
+	files    http.RoundTripper
+	archives map[string]*archiveFS
+	mu       sync.Mutex
+}
+
+// Returns a client for the school's site, its base URL can also point
+// at a local mirror (file://)
+func NewClient(ignoreCertificates bool, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(utils.NewHttpTransport(ignoreCertificates)),
+		Timeout:   timeout,
+	}
+}
+
+func NewTransport(next http.RoundTripper) *Transport {
+	return &Transport{
+		next:     next,
+		files:    http.NewFileTransport(http.Dir("/")),
+		archives: make(map[string]*archiveFS),
+	}
+}
+
+// Returns the archive, reloading it when the file on disk is replaced
+func (t *Transport) archive(archivePath string) (*archiveFS, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if archive, ok := t.archives[archivePath]; ok && archive.modTime.Equal(info.ModTime()) {
+		return archive, nil
+	}
+
+	archive, err := loadArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	t.archives[archivePath] = archive
+
+	return archive, nil
+}
+
+// Splits the path into the archive and the path inside of it, ok is
+// false if the path doesn't point into an archive
+func splitArchivePath(urlPath string) (archivePath string, innerPath string, ok bool) {
+	segments := strings.Split(urlPath, "/")
+	for i, segment := range segments {
+		if isArchive(segment) {
+			return strings.Join(segments[:i+1], "/"), "/" + strings.Join(segments[i+1:], "/"), true
+		}
+	}
+
+	return "", "", false
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "file" {
+		return t.next.RoundTrip(req)
+	}
+
+	archivePath, innerPath, ok := splitArchivePath(req.URL.Path)
+	if !ok {
+		return t.files.RoundTrip(req)
+	}
+
+	archive, err := t.archive(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening snapshot %s: %w", archivePath, err)
+	}
+
+	archiveReq := req.Clone(req.Context())
+	archiveReq.URL.Path = innerPath
+
+	return http.NewFileTransportFS(archive).RoundTrip(archiveReq)
+}
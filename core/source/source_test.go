@@ -0,0 +1,129 @@
+// source/source_test.go
+package source
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("error getting %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error reading %s: %v", url, err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
+func writeSnapshot(t *testing.T, path string, pages map[string]string, modTime time.Time) {
+	t.Helper()
+
+	writer, err := NewSnapshotWriter(path)
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	for name, data := range pages {
+		if err := writer.Add(name, []byte(data), modTime); err != nil {
+			t.Fatalf("error adding %s: %v", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("error closing snapshot: %v", err)
+	}
+}
+
+func TestSplitArchivePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		archive string
+		inner   string
+		ok      bool
+	}{
+		{"/snapshots/school.tar.gz/plany/o1.html", "/snapshots/school.tar.gz", "/plany/o1.html", true},
+		{"/snapshots/school.tgz/lista.html", "/snapshots/school.tgz", "/lista.html", true},
+		{"/snapshots/school/plany/o1.html", "", "", false},
+	}
+
+	for _, test := range tests {
+		archive, inner, ok := splitArchivePath(test.path)
+		if archive != test.archive || inner != test.inner || ok != test.ok {
+			t.Errorf("%s: got %q %q %v, want %q %q %v", test.path, archive, inner, ok, test.archive, test.inner, test.ok)
+		}
+	}
+}
+
+func TestDirectorySnapshot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "school")
+	modTime := time.Date(2024, time.September, 2, 8, 0, 0, 0, time.UTC)
+	writeSnapshot(t, root, map[string]string{"plany/o1.html": "division 1", "/lista.html": "list"}, modTime)
+
+	info, err := os.Stat(filepath.Join(root, "plany", "o1.html"))
+	if err != nil {
+		t.Fatalf("snapshot file wasn't written: %v", err)
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("got modification time %s, want %s", info.ModTime(), modTime)
+	}
+
+	client := NewClient(false, 5*time.Second)
+	if code, body := get(t, client, "file://"+root+"/plany/o1.html"); code != http.StatusOK || body != "division 1" {
+		t.Errorf("got %d %q, want the page", code, body)
+	}
+	if code, body := get(t, client, "file://"+root+"/lista.html"); code != http.StatusOK || body != "list" {
+		t.Errorf("got %d %q, want the list", code, body)
+	}
+	if code, _ := get(t, client, "file://"+root+"/plany/o2.html"); code != http.StatusNotFound {
+		t.Errorf("missing page: got %d, want 404", code)
+	}
+}
+
+func TestArchiveSnapshot(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "school.tar.gz")
+	modTime := time.Date(2024, time.September, 2, 8, 0, 0, 0, time.UTC)
+	writeSnapshot(t, archive, map[string]string{"plany/o1.html": "division 1"}, modTime)
+
+	client := NewClient(false, 5*time.Second)
+	url := "file://" + archive + "/plany/o1.html"
+
+	if code, body := get(t, client, url); code != http.StatusOK || body != "division 1" {
+		t.Errorf("got %d %q, want the page", code, body)
+	}
+	if code, _ := get(t, client, "file://"+archive+"/plany/o2.html"); code != http.StatusNotFound {
+		t.Errorf("missing page: got %d, want 404", code)
+	}
+
+	// A replaced archive is loaded again
+	writeSnapshot(t, archive, map[string]string{"plany/o1.html": "division 1, changed"}, modTime)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(archive, later, later); err != nil {
+		t.Fatalf("error touching archive: %v", err)
+	}
+
+	if code, body := get(t, client, url); code != http.StatusOK || body != "division 1, changed" {
+		t.Errorf("replaced archive: got %d %q, want the new page", code, body)
+	}
+}
+
+func TestOtherSchemesPassThrough(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "upstream")
+	}))
+	defer server.Close()
+
+	if code, body := get(t, NewClient(false, 5*time.Second), server.URL); code != http.StatusOK || body != "upstream" {
+		t.Errorf("got %d %q, want the upstream page", code, body)
+	}
+}
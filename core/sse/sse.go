@@ -2,41 +2,150 @@ package sse
 
 import (
     "fmt"
+    "log/slog"
     "net/http"
+    "sort"
+    "strconv"
     "sync"
     "time"
+
+    "smuggr.xyz/goptivum/core/metrics"
+
+    "github.com/gorilla/websocket"
 )
 
+// PayloadFunc builds the data of an event for the clients that asked for
+// the payload, ok is false if the event has none
+type PayloadFunc func(payload string) (data interface{}, ok bool)
+
+// Event is a message sent to the clients of a hub, events without
+// a type are received by the clients' "message" handlers and events
+// without a topic by every client. Payloads builds alternatives to Data
+// for the clients that ask for a payload
+type Event struct {
+    ID       int64
+    Type     string
+    Topic    string
+    Data     interface{}
+    Payloads PayloadFunc
+
+    payloads *payloadCache
+}
+
+// Payloads are built by the first client that's sent them and shared with
+// the others, they aren't built at all if no client asked for them
+type payloadCache struct {
+    mu     sync.Mutex
+    values map[string]interface{}
+}
+
+// Returns the data sent to the clients that asked for the payload
+func (e *Event) DataFor(payload string) interface{} {
+    if payload == "" || e.Payloads == nil || e.payloads == nil {
+        return e.Data
+    }
+
+    e.payloads.mu.Lock()
+    defer e.payloads.mu.Unlock()
+
+    if data, ok := e.payloads.values[payload]; ok {
+        return data
+    }
+
+    data, ok := e.Payloads(payload)
+    if !ok {
+        data = e.Data
+    }
+    e.payloads.values[payload] = data
+
+    return data
+}
+
+// Sent to a reconnecting client whose missed events aren't buffered
+// anymore (or were sent before a restart), it has to refetch everything
+const ResetEvent = "reset"
+
+const (
+    defaultReplaySize        = 100
+    defaultClientBufferSize  = 16
+    defaultHeartbeatInterval = 15 * time.Second
+    writeTimeout             = 10 * time.Second
+)
+
+// OverflowPolicy decides what happens to a client whose buffer is full
+// when an event is sent, the hub never waits for a client
+type OverflowPolicy int
+
+const (
+    // The client is removed, it reconnects and gets the missed events replayed
+    EvictPolicy OverflowPolicy = iota
+    // The client misses the event and stays connected
+    DropPolicy
+)
+
+// Client receives the events of its topics, or every event without any
 type Client struct {
-    MessageChan chan interface{}
+    MessageChan chan *Event
     Done        chan struct{}
+    LastEventID int64
+    Topics      map[string]bool
+    Payload     string
+}
+
+func (c *Client) Wants(event *Event) bool {
+    return c.Topics == nil || event.Topic == "" || c.Topics[event.Topic]
 }
 
 type Hub struct {
     clients            map[*Client]bool
     mu                 sync.RWMutex
     maxClients         int64
-    broadcast          chan interface{}
+    broadcast          chan *Event
     register           chan *Client
     unregister         chan *Client
     retryDelay         int
     unregisterCallback func()
     registerCallback   func()
+    school             string
+    name               string
+    lastID             int64
+    replay             []*Event
+    replaySize         int
+    clientBufferSize   int
+    overflowPolicy     OverflowPolicy
+    heartbeatInterval  time.Duration
 }
 
 func NewHub(maxClients int64, unregisterCallback func(), registerCallback func()) *Hub {
     return &Hub{
         clients:            make(map[*Client]bool),
         maxClients:         maxClients,
-        broadcast:          make(chan interface{}, 100),
+        broadcast:          make(chan *Event, 100),
         register:           make(chan *Client),
         unregister:         make(chan *Client),
         retryDelay:         3000,
         unregisterCallback: unregisterCallback,
         registerCallback:   registerCallback,
+        // Ids keep increasing across restarts, so a client reconnecting
+        // after one gets a reset instead of unrelated events
+        lastID:             time.Now().UnixMilli(),
+        replaySize:         defaultReplaySize,
+        clientBufferSize:   defaultClientBufferSize,
+        overflowPolicy:     EvictPolicy,
+        heartbeatInterval:  defaultHeartbeatInterval,
     }
 }
 
+// Sets the labels the hub's metrics are reported under
+func (h *Hub) SetLabels(school string, name string) {
+    h.school = school
+    h.name = name
+}
+
+func (h *Hub) logger() *slog.Logger {
+    return slog.With("school", h.school, "hub", h.name)
+}
+
 func (h *Hub) Run() {
     for {
         select {
@@ -44,10 +153,16 @@ func (h *Hub) Run() {
             h.mu.Lock()
             if len(h.clients) < int(h.maxClients) {
                 h.clients[client] = true
-                fmt.Println("client registered, total:", len(h.clients))
+                for _, event := range h.missedEvents(client.LastEventID) {
+                    if client.Wants(event) {
+                        client.MessageChan <- event
+                    }
+                }
+                metrics.SSEClients.WithLabelValues(h.school, h.name).Set(float64(len(h.clients)))
+                h.logger().Debug("client registered", "clients", len(h.clients))
                 h.onRegisterCallback()
             } else {
-                fmt.Printf("max clients reached (%d), client rejected\n", h.maxClients)
+                h.logger().Warn("max clients reached, client rejected", "max_clients", h.maxClients)
                 close(client.MessageChan)
                 close(client.Done)
             }
@@ -55,38 +170,126 @@ func (h *Hub) Run() {
 
         case client := <-h.unregister:
             h.mu.Lock()
-            if _, ok := h.clients[client]; ok {
-                delete(h.clients, client)
-                close(client.MessageChan)
-                close(client.Done)
-                h.onUnregisterCallback()
-                fmt.Println("client unregistered, total:", len(h.clients))
+            if h.removeClient(client) {
+                h.logger().Debug("client unregistered", "clients", len(h.clients))
             }
             h.mu.Unlock()
 
-        case message := <-h.broadcast:
-            h.mu.RLock()
-            for client := range h.clients {
-                select {
-                case client.MessageChan <- message:
-                case <-time.After(10 * time.Second):
-                    fmt.Println("client message timed out, removing client")
-                    h.mu.RUnlock()
-                    h.unregister <- client
-                    h.onUnregisterCallback()
-                    h.mu.RLock()
-                }
-            }
-            h.mu.RUnlock()
+        case event := <-h.broadcast:
+            h.mu.Lock()
+            h.lastID++
+            event.ID = h.lastID
+            h.remember(event)
+            h.fanOut(event)
+            h.mu.Unlock()
+        }
+    }
+}
+
+// Queues the event for every client that wants it without waiting, a client
+// whose buffer is full is handled by the overflow policy. It's called with
+// the lock held
+func (h *Hub) fanOut(event *Event) {
+    for client := range h.clients {
+        if !client.Wants(event) {
+            continue
         }
+
+        select {
+        case client.MessageChan <- event:
+            continue
+        default:
+        }
+
+        metrics.SSEDroppedBroadcasts.WithLabelValues(h.school, h.name, "client_buffer_full").Inc()
+        if h.overflowPolicy == DropPolicy {
+            h.logger().Warn("client buffer full, dropping message")
+            continue
+        }
+
+        // Deleting the current entry while ranging over the map is safe
+        h.removeClient(client)
+        metrics.SSEEvictedClients.WithLabelValues(h.school, h.name).Inc()
+        h.logger().Warn("client buffer full, evicting client", "clients", len(h.clients))
+    }
+}
+
+// Removes the client and closes its channels, it returns false if it was
+// already removed. It's called with the lock held
+func (h *Hub) removeClient(client *Client) bool {
+    if _, ok := h.clients[client]; !ok {
+        return false
+    }
+
+    delete(h.clients, client)
+    metrics.SSEClients.WithLabelValues(h.school, h.name).Set(float64(len(h.clients)))
+    close(client.MessageChan)
+    close(client.Done)
+    h.onUnregisterCallback()
+
+    return true
+}
+
+// Keeps the event for the clients that reconnect, dropping the oldest one
+// when full. Its payloads aren't kept, replayed events only carry their data
+func (h *Hub) remember(event *Event) {
+    if h.replaySize <= 0 {
+        return
     }
+
+    if len(h.replay) >= h.replaySize {
+        h.replay = h.replay[1:]
+    }
+
+    remembered := *event
+    remembered.Payloads = nil
+    remembered.payloads = nil
+    h.replay = append(h.replay, &remembered)
 }
 
+// Returns the events sent after lastID, or a reset if some of them aren't
+// buffered anymore, a client without a last event id has missed nothing
+func (h *Hub) missedEvents(lastID int64) []*Event {
+    if lastID == 0 || lastID == h.lastID {
+        return nil
+    }
+
+    if lastID < h.lastID && len(h.replay) > 0 && lastID >= h.replay[0].ID-1 {
+        i := sort.Search(len(h.replay), func(i int) bool {
+            return h.replay[i].ID > lastID
+        })
+        return h.replay[i:]
+    }
+
+    return []*Event{{ID: h.lastID, Type: ResetEvent, Data: ResetEvent}}
+}
+
+// Sends the message as an event without a type
 func (h *Hub) Broadcast(message interface{}) {
+    h.BroadcastEvent("", message)
+}
+
+// Sends the data as an event of the given type to every client
+func (h *Hub) BroadcastEvent(eventType string, data interface{}) {
+    h.BroadcastTopic("", eventType, data)
+}
+
+// Sends the data as an event of the given type to the clients subscribed to the topic
+func (h *Hub) BroadcastTopic(topic string, eventType string, data interface{}) {
+    h.Send(Event{Type: eventType, Topic: topic, Data: data})
+}
+
+// Sends a copy of the event, the hub gives it the next id
+func (h *Hub) Send(event Event) {
+    if event.Payloads != nil {
+        event.payloads = &payloadCache{values: make(map[string]interface{})}
+    }
+
     select {
-    case h.broadcast <- message:
+    case h.broadcast <- &event:
     default:
-        fmt.Println("broadcast channel full, dropping message")
+        metrics.SSEDroppedBroadcasts.WithLabelValues(h.school, h.name, "queue_full").Inc()
+        h.logger().Warn("broadcast channel full, dropping message")
     }
 }
 
@@ -94,8 +297,159 @@ func (h *Hub) SetRetryDelay(ms int) {
     h.retryDelay = ms
 }
 
+// Sets how many of the latest events are kept for reconnecting clients,
+// it has to be called before the hub is run
+func (h *Hub) SetReplaySize(size int) {
+    h.replaySize = size
+}
+
+// Sets how many events can wait for a client on top of the replayed ones,
+// it applies to the clients connecting afterwards
+func (h *Hub) SetClientBufferSize(size int) {
+    h.clientBufferSize = size
+}
+
+// Sets what happens to clients that can't keep up, it has to be called
+// before the hub is run
+func (h *Hub) SetOverflowPolicy(policy OverflowPolicy) {
+    h.overflowPolicy = policy
+}
+
+// Sets how often idle connections get a heartbeat so proxies keep them
+// open, 0 disables them
+func (h *Hub) SetHeartbeatInterval(interval time.Duration) {
+    h.heartbeatInterval = interval
+}
+
+// Returns a channel ticking at the heartbeat interval, or a nil one that
+// never does if heartbeats are disabled
+func (h *Hub) heartbeats() (<-chan time.Time, func()) {
+    if h.heartbeatInterval <= 0 {
+        return nil, func() {}
+    }
+
+    ticker := time.NewTicker(h.heartbeatInterval)
+    return ticker.C, ticker.Stop
+}
+
+// Clients pick the payload of the events with the "payload" query parameter
+func parsePayload(r *http.Request) string {
+    return r.URL.Query().Get("payload")
+}
+
+// EventSource sends the Last-Event-ID header when it reconnects, the
+// query parameter is for the clients that can't set headers
+func parseLastEventID(r *http.Request) int64 {
+    value := r.Header.Get("Last-Event-ID")
+    if value == "" {
+        value = r.URL.Query().Get("lastEventId")
+    }
+
+    id, err := strconv.ParseInt(value, 10, 64)
+    if err != nil {
+        return 0
+    }
+
+    return id
+}
+
+func writeEvent(w http.ResponseWriter, event *Event, payload string) {
+    if event.ID != 0 {
+        fmt.Fprintf(w, "id: %d\n", event.ID)
+    }
+    if event.Type != "" {
+        fmt.Fprintf(w, "event: %s\n", event.Type)
+    }
+    fmt.Fprintf(w, "data: %v\n\n", event.DataFor(payload))
+}
+
+// Streams every event of the hub
 func (h *Hub) Handler() http.HandlerFunc {
+    return h.SubscribeHandler(nil)
+}
+
+func topicSet(topics []string) map[string]bool {
+    if len(topics) == 0 {
+        return nil
+    }
+
+    subscribed := make(map[string]bool, len(topics))
+    for _, topic := range topics {
+        subscribed[topic] = true
+    }
+
+    return subscribed
+}
+
+// Checks the topics a client connects with against the limit
+// its later subscriptions are held to, duplicates count once
+func CheckTopics(topics []string) error {
+    if len(topicSet(topics)) > maxTopics {
+        return ErrTooManyTopics
+    }
+
+    return nil
+}
+
+// The replayed events are queued before any new one
+func (h *Hub) newClient(r *http.Request, topics []string) *Client {
+    return &Client{
+        MessageChan: make(chan *Event, h.clientBufferSize+h.replaySize),
+        Done:        make(chan struct{}),
+        LastEventID: parseLastEventID(r),
+        Topics:      topicSet(topics),
+        Payload:     parsePayload(r),
+    }
+}
+
+// Adds the topics of the client, one subscribed to every event only
+// gets the events of its topics from then on. Nothing is added if the
+// client would end up with too many topics
+func (h *Hub) Subscribe(client *Client, topics []string) error {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    added := make(map[string]bool)
+    for _, topic := range topics {
+        if !client.Topics[topic] {
+            added[topic] = true
+        }
+    }
+    if len(client.Topics)+len(added) > maxTopics {
+        return ErrTooManyTopics
+    }
+
+    if client.Topics == nil {
+        client.Topics = make(map[string]bool, len(topics))
+    }
+    for _, topic := range topics {
+        client.Topics[topic] = true
+    }
+
+    return nil
+}
+
+// Removes the topics of the client, it still gets the events without a topic
+// and a client subscribed to every event stays subscribed to them
+func (h *Hub) Unsubscribe(client *Client, topics []string) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    for _, topic := range topics {
+        delete(client.Topics, topic)
+    }
+}
+
+// Streams the events of the given topics (and the ones without a topic),
+// every event if there are no topics. WebSocket upgrade requests are
+// served over a WebSocket instead
+func (h *Hub) SubscribeHandler(topics []string) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
+        if websocket.IsWebSocketUpgrade(r) {
+            h.serveWebSocket(w, r, topics)
+            return
+        }
+
         w.Header().Set("Content-Type", "text/event-stream")
         w.Header().Set("Cache-Control", "no-cache")
         w.Header().Set("Connection", "keep-alive")
@@ -103,16 +457,15 @@ func (h *Hub) Handler() http.HandlerFunc {
 
         if h.retryDelay > 0 {
             fmt.Fprintf(w, "retry: %d\n\n", h.retryDelay)
-            if f, ok := w.(http.Flusher); ok {
-                f.Flush()
-            }
         }
 
-        client := &Client{
-            MessageChan: make(chan interface{}, 10),
-            Done:        make(chan struct{}),
+        // The headers are sent right away, so clients know they're connected
+        if f, ok := w.(http.Flusher); ok {
+            f.Flush()
         }
 
+        client := h.newClient(r, topics)
+
         h.register <- client
         notify := r.Context().Done()
 
@@ -121,19 +474,26 @@ func (h *Hub) Handler() http.HandlerFunc {
             h.unregister <- client
         }()
 
+        heartbeats, stop := h.heartbeats()
+        defer stop()
+
         for {
             select {
-            case message, ok := <-client.MessageChan:
+            case event, ok := <-client.MessageChan:
                 if !ok {
                     return
                 }
-                fmt.Fprintf(w, "data: %v\n\n", message)
-                if f, ok := w.(http.Flusher); ok {
-                    f.Flush()
-                }
+                writeEvent(w, event, client.Payload)
+            case <-heartbeats:
+                // Comments are ignored by EventSource
+                fmt.Fprint(w, ": heartbeat\n\n")
             case <-client.Done:
                 return
             }
+
+            if f, ok := w.(http.Flusher); ok {
+                f.Flush()
+            }
         }
     }
 }
@@ -142,11 +502,6 @@ func (h *Hub) SendMessage(message string) {
     h.Broadcast(message)
 }
 
-func (h *Hub) SendMessageWithID(id int, message string) {
-    formattedMessage := fmt.Sprintf("id: %d\ndata: %s\n\n", id, message)
-    h.Broadcast(formattedMessage)
-}
-
 func (h *Hub) SendPeriodicMessages(interval time.Duration, messageFunc func() string) {
     ticker := time.NewTicker(interval)
     go func() {
@@ -156,7 +511,7 @@ func (h *Hub) SendPeriodicMessages(interval time.Duration, messageFunc func() st
                 message := messageFunc()
                 h.Broadcast(message)
             case <-time.After(10 * time.Second):
-                fmt.Println("periodic message timed out")
+                h.logger().Warn("periodic message timed out")
                 return
             }
         }
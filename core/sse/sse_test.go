@@ -0,0 +1,439 @@
+// sse/sse_test.go
+package sse
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	ID   int64
+	Type string
+	Data string
+}
+
+func readEvents(t *testing.T, url string, lastEventID int64) <-chan testEvent {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf("error creating request: %v", err)
+	}
+	if lastEventID != 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error connecting to events: %v", err)
+	}
+
+	events := make(chan testEvent, 10)
+	go func() {
+		defer resp.Body.Close()
+
+		var event testEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if event.Data != "" {
+					events <- event
+				}
+				event = testEvent{}
+			case strings.HasPrefix(line, "id: "):
+				event.ID, _ = strconv.ParseInt(strings.TrimPrefix(line, "id: "), 10, 64)
+			case strings.HasPrefix(line, "event: "):
+				event.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	return events
+}
+
+func waitForEvent(t *testing.T, events <-chan testEvent) testEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+
+	return testEvent{}
+}
+
+func waitForClients(t *testing.T, hub *Hub, clients int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.GetConnectedClients() != clients {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d client(s)", clients)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReplayLastEventID(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	hub.SetReplaySize(3)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		// The events streams never end on their own
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	live := readEvents(t, server.URL, 0)
+	waitForClients(t, hub, 1)
+
+	var sent []testEvent
+	for index := 1; index <= 5; index++ {
+		hub.BroadcastEvent("division.updated", index)
+
+		event := waitForEvent(t, live)
+		if event.Type != "division.updated" || event.Data != strconv.Itoa(index) {
+			t.Fatalf("event: got %s %q, want division.updated %q", event.Type, event.Data, strconv.Itoa(index))
+		}
+		if len(sent) > 0 && event.ID <= sent[len(sent)-1].ID {
+			t.Fatalf("event id %d isn't greater than %d", event.ID, sent[len(sent)-1].ID)
+		}
+		sent = append(sent, event)
+	}
+
+	// Only the last 3 events are buffered, which is all this client missed
+	reconnected := readEvents(t, server.URL, sent[1].ID)
+	for _, want := range sent[2:] {
+		if event := waitForEvent(t, reconnected); event != want {
+			t.Errorf("replayed event: got %+v, want %+v", event, want)
+		}
+	}
+
+	// This one missed an event that isn't buffered anymore
+	stale := readEvents(t, server.URL, sent[0].ID)
+	if event := waitForEvent(t, stale); event.Type != ResetEvent || event.ID != sent[4].ID {
+		t.Errorf("stale client: got %+v, want a %s with id %d", event, ResetEvent, sent[4].ID)
+	}
+
+	// Clients that are up to date only get the new events
+	waitForClients(t, hub, 3)
+	upToDate := readEvents(t, server.URL, sent[4].ID)
+	waitForClients(t, hub, 4)
+
+	hub.BroadcastEvent("division.removed", 6)
+	if event := waitForEvent(t, upToDate); event.Type != "division.removed" || event.ID != sent[4].ID+1 {
+		t.Errorf("new event: got %+v, want division.removed with id %d", event, sent[4].ID+1)
+	}
+}
+
+func TestTopicSubscriptions(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	go hub.Run()
+
+	division := httptest.NewServer(hub.SubscribeHandler([]string{"division:3"}))
+	multiplexed := httptest.NewServer(hub.SubscribeHandler([]string{"division:3", "teacher:12"}))
+	everything := httptest.NewServer(hub.Handler())
+	defer func() {
+		for _, server := range []*httptest.Server{division, multiplexed, everything} {
+			server.CloseClientConnections()
+			server.Close()
+		}
+	}()
+
+	divisionEvents := readEvents(t, division.URL, 0)
+	multiplexedEvents := readEvents(t, multiplexed.URL, 0)
+	allEvents := readEvents(t, everything.URL, 0)
+	waitForClients(t, hub, 3)
+
+	hub.BroadcastTopic("division:4", "division.updated", 4)
+	hub.BroadcastTopic("teacher:12", "teacher.updated", 12)
+	hub.BroadcastTopic("division:3", "division.updated", 3)
+	hub.BroadcastEvent("announcement", "everyone")
+
+	expected := map[string][]string{
+		"division":    {"3", "everyone"},
+		"multiplexed": {"12", "3", "everyone"},
+		"everything":  {"4", "12", "3", "everyone"},
+	}
+	streams := map[string]<-chan testEvent{
+		"division":    divisionEvents,
+		"multiplexed": multiplexedEvents,
+		"everything":  allEvents,
+	}
+
+	for name, want := range expected {
+		for _, data := range want {
+			if event := waitForEvent(t, streams[name]); event.Data != data {
+				t.Errorf("%s client: got %q, want %q", name, event.Data, data)
+			}
+		}
+
+		select {
+		case event := <-streams[name]:
+			t.Errorf("%s client: got unexpected %+v", name, event)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestPayloads(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	index := readEvents(t, server.URL, 0)
+	json := readEvents(t, server.URL+"?payload=json", 0)
+	otherJSON := readEvents(t, server.URL+"?payload=json", 0)
+	diff := readEvents(t, server.URL+"?payload=diff", 0)
+	waitForClients(t, hub, 4)
+
+	built := make(chan string, 10)
+	hub.Send(Event{
+		Type: "division.updated",
+		Data: 3,
+		Payloads: func(payload string) (interface{}, bool) {
+			built <- payload
+			if payload != "json" {
+				return nil, false
+			}
+			return `{"index":3}`, true
+		},
+	})
+	hub.BroadcastEvent("division.removed", 4)
+
+	if event := waitForEvent(t, index); event.Data != "3" {
+		t.Errorf("default payload: got %q, want \"3\"", event.Data)
+	}
+	for _, stream := range []<-chan testEvent{json, otherJSON} {
+		if event := waitForEvent(t, stream); event.Data != `{"index":3}` {
+			t.Errorf("json payload: got %q, want the item", event.Data)
+		}
+	}
+
+	// Events without the payload fall back to their data
+	if event := waitForEvent(t, diff); event.Data != "3" {
+		t.Errorf("diff payload the event doesn't have: got %q, want \"3\"", event.Data)
+	}
+	if event := waitForEvent(t, json); event.Data != "4" {
+		t.Errorf("json payload of an event without one: got %q, want \"4\"", event.Data)
+	}
+
+	// Every payload is built once, whatever the number of clients asking for it
+	close(built)
+	var payloads []string
+	for payload := range built {
+		payloads = append(payloads, payload)
+	}
+	if len(payloads) != 2 {
+		t.Errorf("built payloads: got %v, want json and diff once", payloads)
+	}
+}
+
+func TestPayloadsAreLazy(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	index := readEvents(t, server.URL, 0)
+	waitForClients(t, hub, 1)
+
+	built := false
+	hub.Send(Event{
+		Type: "division.updated",
+		Data: 3,
+		Payloads: func(payload string) (interface{}, bool) {
+			built = true
+			return `{"index":3}`, true
+		},
+	})
+
+	first := waitForEvent(t, index)
+	if first.Data != "3" {
+		t.Errorf("default payload: got %q, want \"3\"", first.Data)
+	}
+
+	// The replay buffer doesn't keep the payloads
+	replayed := readEvents(t, server.URL+"?payload=json", first.ID-1)
+	if event := waitForEvent(t, replayed); event.ID != first.ID || event.Data != "3" {
+		t.Errorf("replayed event: got %+v, want %d with its data", event, first.ID)
+	}
+
+	if built {
+		t.Error("payload was built without any client asking for it")
+	}
+}
+
+// Registers a client that never reads its events, like one behind a stalled connection
+func registerStuckClient(t *testing.T, hub *Hub, buffer int) *Client {
+	t.Helper()
+
+	client := &Client{
+		MessageChan: make(chan *Event, buffer),
+		Done:        make(chan struct{}),
+	}
+	hub.register <- client
+
+	return client
+}
+
+// Broadcasts more events than the stuck client can buffer and checks that
+// the live client gets every one of them right away
+func broadcastPastStuckClient(t *testing.T, hub *Hub, live <-chan testEvent) {
+	t.Helper()
+
+	start := time.Now()
+	for index := 1; index <= 20; index++ {
+		hub.BroadcastEvent("division.updated", index)
+
+		if event := waitForEvent(t, live); event.Data != strconv.Itoa(index) {
+			t.Fatalf("live client: got %q, want %q", event.Data, strconv.Itoa(index))
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("live client was delayed by the stuck one, took %s", elapsed)
+	}
+}
+
+func TestStuckClientIsEvicted(t *testing.T) {
+	unregistered := make(chan struct{}, 10)
+	hub := NewHub(10, func() { unregistered <- struct{}{} }, nil)
+	hub.SetRetryDelay(0)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	live := readEvents(t, server.URL, 0)
+	stuck := registerStuckClient(t, hub, 2)
+	waitForClients(t, hub, 2)
+
+	broadcastPastStuckClient(t, hub, live)
+
+	select {
+	case <-stuck.Done:
+	case <-time.After(time.Second):
+		t.Fatal("stuck client wasn't evicted")
+	}
+	waitForClients(t, hub, 1)
+
+	// The hub still accepts the unregistration of a client it already evicted
+	hub.unregister <- stuck
+	if len(unregistered) != 1 {
+		t.Errorf("unregister callback: got %d calls, want 1", len(unregistered))
+	}
+
+	// Its buffered events are still delivered before the channel ends
+	var buffered []*Event
+	for event := range stuck.MessageChan {
+		buffered = append(buffered, event)
+	}
+	if len(buffered) != 2 || buffered[0].Data != 1 || buffered[1].Data != 2 {
+		t.Errorf("stuck client buffer: got %d events, want the first 2", len(buffered))
+	}
+}
+
+func TestStuckClientDropsEvents(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	hub.SetOverflowPolicy(DropPolicy)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	live := readEvents(t, server.URL, 0)
+	stuck := registerStuckClient(t, hub, 2)
+	waitForClients(t, hub, 2)
+
+	broadcastPastStuckClient(t, hub, live)
+
+	if clients := hub.GetConnectedClients(); clients != 2 {
+		t.Errorf("got %d clients, want the stuck one to stay connected", clients)
+	}
+
+	// Once it reads again it gets the new events, the ones in between are lost
+	<-stuck.MessageChan
+	<-stuck.MessageChan
+	hub.BroadcastEvent("division.updated", 21)
+	select {
+	case event := <-stuck.MessageChan:
+		if event.Data != 21 {
+			t.Errorf("stuck client: got %v, want 21", event.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stuck client's event")
+	}
+}
+
+func TestHeartbeats(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	hub.SetRetryDelay(0)
+	hub.SetHeartbeatInterval(10 * time.Millisecond)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("error connecting to events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	heartbeat := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				heartbeat <- line
+				return
+			}
+		}
+	}()
+
+	select {
+	case line := <-heartbeat:
+		if line != ": heartbeat" {
+			t.Errorf("got %q, want a heartbeat comment", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a heartbeat")
+	}
+}
@@ -0,0 +1,197 @@
+// sse/websocket.go
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// Framings of the WebSocket messages, picked with the "format" query parameter
+const (
+	JSONFormat     = "json"
+	ProtobufFormat = "protobuf"
+)
+
+// Actions of the requests WebSocket clients send to change their topics
+const (
+	SubscribeAction   = "subscribe"
+	UnsubscribeAction = "unsubscribe"
+)
+
+const (
+	// Requests only carry a few topics
+	maxRequestSize = 4096
+	// A client subscribing to every item of a school individually stays below it
+	maxTopics = 256
+)
+
+var ErrTooManyTopics = errors.New("too many topics")
+
+// Browsers can only open WebSockets from these origins (or the API's own
+// host), the same ones CORS allows. Clients that aren't browsers don't send
+// an origin and are always allowed
+var AllowedOrigins []string
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: checkOrigin,
+}
+
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Reports whether the format is one the WebSocket transport can frame events in
+func ValidFormat(format string) bool {
+	return format == "" || format == JSONFormat || format == ProtobufFormat
+}
+
+func streamEvent(event *Event, payload string) *models.StreamEvent {
+	return &models.StreamEvent{
+		Id:    event.ID,
+		Type:  event.Type,
+		Topic: event.Topic,
+		Data:  fmt.Sprint(event.DataFor(payload)),
+	}
+}
+
+// JSON events are sent as text messages and protobuf ones as binary messages
+func writeMessage(conn *websocket.Conn, event *Event, payload string, format string) error {
+	message := streamEvent(event, payload)
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	if format == ProtobufFormat {
+		data, err := proto.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("error marshalling event: %w", err)
+		}
+		return conn.WriteMessage(websocket.BinaryMessage, data)
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("error marshalling event: %w", err)
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Requests are framed by their message type, whatever format the events use
+func parseRequest(messageType int, data []byte) (*models.StreamRequest, error) {
+	var request models.StreamRequest
+
+	var err error
+	if messageType == websocket.BinaryMessage {
+		err = proto.Unmarshal(data, &request)
+	} else {
+		err = json.Unmarshal(data, &request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// Applies the client's subscription requests until the connection is closed
+func (h *Hub) readRequests(conn *websocket.Conn, client *Client) {
+	defer func() {
+		h.unregister <- client
+	}()
+
+	conn.SetReadLimit(maxRequestSize)
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		request, err := parseRequest(messageType, data)
+		if err != nil {
+			h.logger().Debug("invalid websocket request", "error", err)
+			continue
+		}
+
+		switch request.Action {
+		case SubscribeAction:
+			if err := h.Subscribe(client, request.Topics); err != nil {
+				h.logger().Debug("websocket subscription rejected", "topics", len(request.Topics), "error", err)
+			}
+		case UnsubscribeAction:
+			h.Unsubscribe(client, request.Topics)
+		default:
+			h.logger().Debug("unknown websocket action", "action", request.Action)
+		}
+	}
+}
+
+// Tells a client the hub rejected or removed it, so it reconnects later
+func closeRemoved(conn *websocket.Conn) {
+	message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "")
+	conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout))
+}
+
+// Streams the events like the SSE handler does, the client counts
+// towards the same limit and callbacks
+func (h *Hub) serveWebSocket(w http.ResponseWriter, r *http.Request, topics []string) {
+	format := r.URL.Query().Get("format")
+	if !ValidFormat(format) {
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger().Debug("websocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	client := h.newClient(r, topics)
+	h.register <- client
+	go h.readRequests(conn, client)
+
+	heartbeats, stop := h.heartbeats()
+	defer stop()
+
+	for {
+		select {
+		case event, ok := <-client.MessageChan:
+			if !ok {
+				closeRemoved(conn)
+				return
+			}
+			if err := writeMessage(conn, event, client.Payload, format); err != nil {
+				h.logger().Debug("websocket write failed", "error", err)
+				return
+			}
+		case <-heartbeats:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-client.Done:
+			closeRemoved(conn)
+			return
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// sse/websocket_test.go
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+func dialWebSocket(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("error dialing %s: %v", url, err)
+	}
+
+	return conn
+}
+
+func readStreamEvent(t *testing.T, conn *websocket.Conn) *models.StreamEvent {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("error reading event: %v", err)
+	}
+
+	var event models.StreamEvent
+	if messageType == websocket.BinaryMessage {
+		err = proto.Unmarshal(data, &event)
+	} else {
+		err = json.Unmarshal(data, &event)
+	}
+	if err != nil {
+		t.Fatalf("error parsing event: %v", err)
+	}
+
+	return &event
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.SubscribeHandler([]string{"division:3"}))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server, "")
+	defer conn.Close()
+	waitForClients(t, hub, 1)
+
+	hub.BroadcastTopic("division:4", "division.updated", 4)
+	hub.BroadcastTopic("division:3", "division.updated", 3)
+	if event := readStreamEvent(t, conn); event.Type != "division.updated" || event.Data != "3" {
+		t.Fatalf("event: got %+v, want division.updated 3", event)
+	}
+
+	requests := []string{
+		`{"action":"subscribe","topics":["teacher:12"]}`,
+		`{"action":"unsubscribe","topics":["division:3"]}`,
+	}
+	for _, request := range requests {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(request)); err != nil {
+			t.Fatalf("error sending request: %v", err)
+		}
+	}
+
+	// The requests are applied by another goroutine, so the events are
+	// broadcast until the last one is seen
+	deadline := time.Now().Add(5 * time.Second)
+	for !hub.clientTopics(t)["teacher:12"] || hub.clientTopics(t)["division:3"] {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the requests")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	hub.BroadcastTopic("division:3", "division.updated", 3)
+	hub.BroadcastTopic("teacher:12", "teacher.updated", 12)
+	if event := readStreamEvent(t, conn); event.Type != "teacher.updated" || event.Data != "12" {
+		t.Errorf("event after the requests: got %+v, want teacher.updated 12", event)
+	}
+}
+
+// Returns the topics of the only client of the hub
+func (h *Hub) clientTopics(t *testing.T) map[string]bool {
+	t.Helper()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	topics := make(map[string]bool)
+	for client := range h.clients {
+		for topic := range client.Topics {
+			topics[topic] = true
+		}
+	}
+
+	return topics
+}
+
+func TestWebSocketProtobuf(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer server.Close()
+
+	conn := dialWebSocket(t, server, "?format=protobuf&payload=json")
+	defer conn.Close()
+	waitForClients(t, hub, 1)
+
+	hub.Send(Event{
+		Type:  "division.updated",
+		Topic: "division:3",
+		Data:  3,
+		Payloads: func(payload string) (interface{}, bool) {
+			return `{"index":3}`, payload == "json"
+		},
+	})
+
+	event := readStreamEvent(t, conn)
+	if event.Id == 0 || event.Type != "division.updated" || event.Topic != "division:3" || event.Data != `{"index":3}` {
+		t.Errorf("event: got %+v, want division.updated with the json payload", event)
+	}
+}
+
+func TestWebSocketSharesMaxClients(t *testing.T) {
+	registered := 0
+	hub := NewHub(2, nil, func() { registered++ })
+	hub.SetRetryDelay(0)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer func() {
+		server.CloseClientConnections()
+		server.Close()
+	}()
+
+	readEvents(t, server.URL, 0)
+	conn := dialWebSocket(t, server, "")
+	defer conn.Close()
+	waitForClients(t, hub, 2)
+
+	rejected := dialWebSocket(t, server, "")
+	defer rejected.Close()
+
+	rejected.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := rejected.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseTryAgainLater) {
+		t.Errorf("client over the limit: got %v, want a try again later close", err)
+	}
+
+	if clients := hub.GetConnectedClients(); clients != 2 || registered != 2 {
+		t.Errorf("got %d clients and %d registrations, want 2 of both", clients, registered)
+	}
+}
+
+func TestWebSocketOrigins(t *testing.T) {
+	AllowedOrigins = []string{"https://zsem.smuggr.xyz"}
+	defer func() {
+		AllowedOrigins = nil
+	}()
+
+	hub := NewHub(10, nil, nil)
+	go hub.Run()
+
+	server := httptest.NewServer(hub.Handler())
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true},
+		{"https://zsem.smuggr.xyz", true},
+		{server.URL, true},
+		{"https://example.com", false},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		if test.origin != "" {
+			header.Set("Origin", test.origin)
+		}
+
+		conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+		if conn != nil {
+			conn.Close()
+		}
+
+		if test.allowed && err != nil {
+			t.Errorf("origin %q: got %v, want it allowed", test.origin, err)
+		}
+		if !test.allowed && (err == nil || resp.StatusCode != http.StatusForbidden) {
+			t.Errorf("origin %q: got %v, want it forbidden", test.origin, err)
+		}
+	}
+}
+
+func TestSubscribeTopicLimit(t *testing.T) {
+	hub := NewHub(10, nil, nil)
+	client := &Client{}
+
+	topics := make([]string, maxTopics)
+	for i := range topics {
+		topics[i] = fmt.Sprintf("division:%d", i)
+	}
+
+	if err := hub.Subscribe(client, topics); err != nil {
+		t.Fatalf("subscribing to %d topics: %v", maxTopics, err)
+	}
+
+	// Topics the client already has don't count twice
+	if err := hub.Subscribe(client, topics[:10]); err != nil {
+		t.Errorf("subscribing again: got %v, want no error", err)
+	}
+
+	if err := hub.Subscribe(client, []string{"teacher:1"}); err != ErrTooManyTopics {
+		t.Errorf("subscribing past the limit: got %v, want %v", err, ErrTooManyTopics)
+	}
+	if len(client.Topics) != maxTopics {
+		t.Errorf("got %d topics, want %d", len(client.Topics), maxTopics)
+	}
+}
+
+func TestCheckTopics(t *testing.T) {
+	topics := make([]string, maxTopics)
+	for i := range topics {
+		topics[i] = fmt.Sprintf("division:%d", i)
+	}
+
+	if err := CheckTopics(topics); err != nil {
+		t.Errorf("%d topics: got %v, want no error", maxTopics, err)
+	}
+	if err := CheckTopics(append(topics, topics[0])); err != nil {
+		t.Errorf("a duplicated topic: got %v, want no error", err)
+	}
+	if err := CheckTopics(append(topics, "teacher:1")); err != ErrTooManyTopics {
+		t.Errorf("%d topics: got %v, want %v", maxTopics+1, err, ErrTooManyTopics)
+	}
+}
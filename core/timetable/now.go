@@ -0,0 +1,124 @@
+// timetable/now.go
+package timetable
+
+import (
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// Returns the time range of the lesson group, preferring the bell schedule
+func groupTimeRange(lessonGroup *models.LessonGroup, periods *models.Periods) *models.TimeRange {
+	if periods != nil {
+		for _, period := range periods.Periods {
+			if period.Number == lessonGroup.Period && period.TimeRange != nil {
+				return period.TimeRange
+			}
+		}
+	}
+
+	for _, lesson := range lessonGroup.Lessons {
+		if lesson.TimeRange != nil && lesson.TimeRange.Start != nil && lesson.TimeRange.End != nil {
+			return lesson.TimeRange
+		}
+	}
+
+	return nil
+}
+
+func secondsOf(t time.Time) int64 {
+	return int64(t.Hour()*3600 + t.Minute()*60 + t.Second())
+}
+
+// Now finds the lesson group lasting at the given time and the one following
+// it (looking up to a week ahead), along with the seconds left until the
+// current one ends and the next one starts
+func Now(schedule *models.Schedule, periods *models.Periods, t time.Time) *models.NowResponse {
+	day := DayIndex(t)
+	now := secondsOf(t)
+
+	response := &models.NowResponse{
+		Day: day,
+	}
+
+	if schedule == nil || len(schedule.ScheduleDays) == 0 {
+		return response
+	}
+
+	for offset := int64(0); offset <= 7; offset++ {
+		nextDay := (day + offset) % 7
+		if nextDay >= int64(len(schedule.ScheduleDays)) {
+			continue
+		}
+
+		for _, lessonGroup := range schedule.ScheduleDays[nextDay].LessonGroups {
+			timeRange := groupTimeRange(lessonGroup, periods)
+			if timeRange == nil {
+				continue
+			}
+
+			start := Minutes(timeRange.Start)*60 + offset*secondsPerDay
+			end := Minutes(timeRange.End)*60 + offset*secondsPerDay
+
+			if start <= now && now < end {
+				response.Current = lessonGroup
+				response.Remaining = end - now
+				continue
+			}
+
+			if start > now {
+				response.Next = lessonGroup
+				response.NextDay = nextDay
+				response.UntilNext = start - now
+				return response
+			}
+		}
+	}
+
+	return response
+}
+
+// WatchPeriods calls onBoundary with the period that has just started (nil
+// when a break has started) every time a period boundary passes on a day with
+// lessons, the time is read from every tick until ticks is closed or quit is
+func WatchPeriods(ticks <-chan time.Time, getPeriods func() *models.Periods, hasLessons func(day int64) bool, location *time.Location, onBoundary func(*models.Period), quit <-chan struct{}) {
+	var periods *models.Periods
+	var lastRefresh time.Time
+	lastDay := int64(-1)
+	var lastPeriod int64
+
+	for {
+		select {
+		case tick, ok := <-ticks:
+			if !ok {
+				return
+			}
+
+			if lastRefresh.IsZero() || tick.Sub(lastRefresh) > time.Minute {
+				periods = getPeriods()
+				lastRefresh = tick
+			}
+
+			now := tick.In(location)
+			period := PeriodAt(periods, MinutesOf(now))
+
+			var number int64
+			if period != nil {
+				number = period.Number
+			}
+
+			// The first tick of a day only establishes the current state
+			day := DayIndex(now)
+			if day == lastDay && number != lastPeriod && hasLessons(day) {
+				onBoundary(period)
+			}
+			lastDay = day
+			lastPeriod = number
+
+		case <-quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// timetable/now_test.go
+package timetable
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+func timeRange(startHour, startMinute, endHour, endMinute int64) *models.TimeRange {
+	return &models.TimeRange{
+		Start: &models.Timestamp{Hour: startHour, Minute: startMinute},
+		End:   &models.Timestamp{Hour: endHour, Minute: endMinute},
+	}
+}
+
+func bellSchedule() *models.Periods {
+	return &models.Periods{
+		Periods: []*models.Period{
+			{Number: 1, TimeRange: timeRange(8, 0, 8, 45)},
+			{Number: 2, TimeRange: timeRange(8, 55, 9, 40)},
+		},
+	}
+}
+
+func lessonGroup(period int64, lessonTime *models.TimeRange) *models.LessonGroup {
+	return &models.LessonGroup{
+		Period:  period,
+		Lessons: []*models.Lesson{{FullName: "matematyka", TimeRange: lessonTime}},
+	}
+}
+
+// Lessons on monday and friday, period 7 isn't in the bell schedule
+// and period 9 has no time at all
+func weekSchedule() *models.Schedule {
+	return &models.Schedule{
+		ScheduleDays: []*models.ScheduleDay{
+			{LessonGroups: []*models.LessonGroup{
+				lessonGroup(1, nil),
+				lessonGroup(2, nil),
+				lessonGroup(7, timeRange(14, 0, 14, 45)),
+				lessonGroup(9, nil),
+			}},
+			{},
+			{},
+			{},
+			{LessonGroups: []*models.LessonGroup{lessonGroup(1, nil)}},
+		},
+	}
+}
+
+// Returns the given time of the week of 2024-10-14, day 0 is monday
+func weekTime(day, hour, minute int) time.Time {
+	return time.Date(2024, time.October, 14+day, hour, minute, 0, 0, time.UTC)
+}
+
+func groupPeriod(lessonGroup *models.LessonGroup) int64 {
+	if lessonGroup == nil {
+		return -1
+	}
+	return lessonGroup.Period
+}
+
+func TestNow(t *testing.T) {
+	tests := []struct {
+		name          string
+		time          time.Time
+		wantDay       int64
+		wantCurrent   int64
+		wantRemaining int64
+		wantNext      int64
+		wantNextDay   int64
+		wantUntilNext int64
+	}{
+		{"during a lesson", weekTime(0, 8, 10), 0, 1, 35 * 60, 2, 0, 45 * 60},
+		{"during a break", weekTime(0, 8, 50), 0, -1, 0, 2, 0, 5 * 60},
+		{"lesson time without a bell schedule match", weekTime(0, 14, 10), 0, 7, 35 * 60, 1, 4, 4*secondsPerDay - (6*60+10)*60},
+		{"after the last lesson", weekTime(0, 15, 0), 0, -1, 0, 1, 4, 4*secondsPerDay - 7*60*60},
+		{"friday evening", weekTime(4, 18, 0), 4, -1, 0, 1, 0, 3*secondsPerDay - 10*60*60},
+		{"sunday", weekTime(6, 12, 0), 6, -1, 0, 1, 0, 20 * 60 * 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Now(weekSchedule(), bellSchedule(), tt.time)
+
+			if got.Day != tt.wantDay {
+				t.Errorf("day: got %d, want %d", got.Day, tt.wantDay)
+			}
+			if period := groupPeriod(got.Current); period != tt.wantCurrent {
+				t.Errorf("current period: got %d, want %d", period, tt.wantCurrent)
+			}
+			if got.Remaining != tt.wantRemaining {
+				t.Errorf("remaining: got %d, want %d", got.Remaining, tt.wantRemaining)
+			}
+			if period := groupPeriod(got.Next); period != tt.wantNext {
+				t.Errorf("next period: got %d, want %d", period, tt.wantNext)
+			}
+			if got.NextDay != tt.wantNextDay {
+				t.Errorf("next day: got %d, want %d", got.NextDay, tt.wantNextDay)
+			}
+			if got.UntilNext != tt.wantUntilNext {
+				t.Errorf("until next: got %d, want %d", got.UntilNext, tt.wantUntilNext)
+			}
+		})
+	}
+}
+
+func TestNowWithoutSchedule(t *testing.T) {
+	got := Now(nil, bellSchedule(), weekTime(0, 8, 10))
+	if got.Current != nil || got.Next != nil {
+		t.Errorf("got current %v and next %v, want none", got.Current, got.Next)
+	}
+}
+
+// Runs the watcher over the given ticks and returns the numbers of the
+// periods it reported (0 for breaks)
+func watch(ticks []time.Time, getPeriods func() *models.Periods) []int64 {
+	tickChan := make(chan time.Time, len(ticks))
+	for _, tick := range ticks {
+		tickChan <- tick
+	}
+	close(tickChan)
+
+	// Lessons only on monday and friday
+	hasLessons := func(day int64) bool {
+		return day == 0 || day == 4
+	}
+
+	boundaries := []int64{}
+	WatchPeriods(tickChan, getPeriods, hasLessons, time.UTC, func(period *models.Period) {
+		var number int64
+		if period != nil {
+			number = period.Number
+		}
+		boundaries = append(boundaries, number)
+	}, nil)
+
+	return boundaries
+}
+
+func TestWatchPeriods(t *testing.T) {
+	tests := []struct {
+		name  string
+		ticks []time.Time
+		want  []int64
+	}{
+		{
+			"boundaries",
+			[]time.Time{weekTime(0, 7, 59), weekTime(0, 8, 0), weekTime(0, 8, 30), weekTime(0, 8, 45), weekTime(0, 8, 55)},
+			[]int64{1, 0, 2},
+		},
+		{
+			"first tick only establishes the state",
+			[]time.Time{weekTime(0, 8, 10), weekTime(0, 8, 20)},
+			[]int64{},
+		},
+		{
+			"weekend",
+			[]time.Time{weekTime(5, 7, 59), weekTime(5, 8, 0), weekTime(5, 8, 45)},
+			[]int64{},
+		},
+		{
+			"day without lessons",
+			[]time.Time{weekTime(1, 7, 59), weekTime(1, 8, 0), weekTime(1, 8, 45)},
+			[]int64{},
+		},
+		{
+			"day with lessons after a day without them",
+			[]time.Time{weekTime(3, 8, 0), weekTime(4, 7, 59), weekTime(4, 8, 0)},
+			[]int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := watch(tt.ticks, bellSchedule)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchPeriodsRefresh(t *testing.T) {
+	var refreshes int
+	getPeriods := func() *models.Periods {
+		refreshes++
+		// The bell schedule is only known after the first refresh
+		if refreshes == 1 {
+			return nil
+		}
+		return bellSchedule()
+	}
+
+	start := weekTime(0, 7, 59)
+	got := watch([]time.Time{start, start.Add(30 * time.Second), start.Add(61 * time.Second)}, getPeriods)
+
+	if refreshes != 2 {
+		t.Errorf("refreshes: got %d, want 2", refreshes)
+	}
+	if want := []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,194 @@
+// timetable/rooms.go
+package timetable
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+type slot struct {
+	Day    int64
+	Period int64
+}
+
+type RoomsFilter struct {
+	Prefix   string
+	Floor    int64
+	HasFloor bool
+}
+
+// Answers which rooms are free in a given period, it's built from the stored
+// room schedules and updated room by room as they change
+type RoomsIndex struct {
+	rooms    []*models.Room
+	occupied map[slot]map[int64]bool
+	mu       sync.RWMutex
+}
+
+func NewRoomsIndex() *RoomsIndex {
+	return &RoomsIndex{
+		occupied: make(map[slot]map[int64]bool),
+	}
+}
+
+// Returns the floor of a room, derived from designators like "105" or "012",
+// where everything but the last two digits is the floor number
+func Floor(designator string) (int64, bool) {
+	digits := strings.TrimLeftFunc(designator, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	end := strings.IndexFunc(digits, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	if end != -1 {
+		digits = digits[:end]
+	}
+
+	if len(digits) < 3 {
+		return 0, false
+	}
+
+	floor, err := strconv.ParseInt(digits[:len(digits)-2], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return floor, true
+}
+
+func roomMetadata(room *models.Room) *models.Room {
+	return &models.Room{
+		Index:      room.Index,
+		Designator: room.Designator,
+		FullName:   room.FullName,
+	}
+}
+
+func sortRooms(rooms []*models.Room) {
+	sort.Slice(rooms, func(a, b int) bool {
+		return rooms[a].Designator < rooms[b].Designator
+	})
+}
+
+// Marks the slots with a lesson in the room's schedule as occupied
+func occupy(occupied map[slot]map[int64]bool, room *models.Room) {
+	if room.Schedule == nil {
+		return
+	}
+
+	for day, scheduleDay := range room.Schedule.ScheduleDays {
+		for _, lessonGroup := range scheduleDay.LessonGroups {
+			if len(lessonGroup.Lessons) == 0 {
+				continue
+			}
+
+			key := slot{Day: int64(day), Period: lessonGroup.Period}
+			if occupied[key] == nil {
+				occupied[key] = make(map[int64]bool)
+			}
+			occupied[key][room.Index] = true
+		}
+	}
+}
+
+func (i *RoomsIndex) Rebuild(rooms []*models.Room) {
+	occupied := make(map[slot]map[int64]bool)
+	roomsMetadata := make([]*models.Room, 0, len(rooms))
+
+	for _, room := range rooms {
+		roomsMetadata = append(roomsMetadata, roomMetadata(room))
+		occupy(occupied, room)
+	}
+
+	sortRooms(roomsMetadata)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rooms = roomsMetadata
+	i.occupied = occupied
+}
+
+// Replaces the schedule of a single room, adding the room if it's new
+func (i *RoomsIndex) Update(room *models.Room) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(room.Index)
+	i.rooms = append(i.rooms, roomMetadata(room))
+	sortRooms(i.rooms)
+	occupy(i.occupied, room)
+}
+
+func (i *RoomsIndex) Remove(index int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(index)
+}
+
+// The rooms slice is replaced rather than modified, since FreeRooms hands
+// out its elements and the callers may still be reading them
+func (i *RoomsIndex) remove(index int64) {
+	rooms := make([]*models.Room, 0, len(i.rooms))
+	for _, room := range i.rooms {
+		if room.Index != index {
+			rooms = append(rooms, room)
+		}
+	}
+	i.rooms = rooms
+
+	for key, occupied := range i.occupied {
+		delete(occupied, index)
+		if len(occupied) == 0 {
+			delete(i.occupied, key)
+		}
+	}
+}
+
+func (i *RoomsIndex) FreeRooms(day, period int64, filter RoomsFilter) []*models.Room {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	freeRooms := []*models.Room{}
+	occupied := i.occupied[slot{Day: day, Period: period}]
+
+	for _, room := range i.rooms {
+		if occupied[room.Index] {
+			continue
+		}
+
+		if filter.Prefix != "" && !strings.HasPrefix(strings.ToLower(room.Designator), strings.ToLower(filter.Prefix)) {
+			continue
+		}
+
+		if filter.HasFloor {
+			if floor, ok := Floor(room.Designator); !ok || floor != filter.Floor {
+				continue
+			}
+		}
+
+		freeRooms = append(freeRooms, room)
+	}
+
+	return freeRooms
+}
+
+// Reports whether any room has a lesson on the given day, days
+// without lessons (like weekends) are days off for the whole school
+func (i *RoomsIndex) HasLessons(day int64) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for key := range i.occupied {
+		if key.Day == day {
+			return true
+		}
+	}
+
+	return false
+}
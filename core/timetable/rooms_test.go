@@ -0,0 +1,106 @@
+// timetable/rooms_test.go
+package timetable
+
+import (
+	"reflect"
+	"testing"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+// Returns a room with a lesson on monday in each of the given periods
+func roomWithLessons(index int64, designator string, periods ...int64) *models.Room {
+	day := &models.ScheduleDay{}
+	for _, period := range periods {
+		day.LessonGroups = append(day.LessonGroups, &models.LessonGroup{
+			Period:  period,
+			Lessons: []*models.Lesson{{FullName: "matematyka"}},
+		})
+	}
+
+	return &models.Room{
+		Index:      index,
+		Designator: designator,
+		Schedule:   &models.Schedule{ScheduleDays: []*models.ScheduleDay{day}},
+	}
+}
+
+func freeDesignators(index *RoomsIndex, period int64) []string {
+	designators := []string{}
+	for _, room := range index.FreeRooms(0, period, RoomsFilter{}) {
+		designators = append(designators, room.Designator)
+	}
+	return designators
+}
+
+func TestRoomsIndexUpdate(t *testing.T) {
+	index := NewRoomsIndex()
+	index.Rebuild([]*models.Room{
+		roomWithLessons(1, "101", 1, 2),
+		roomWithLessons(2, "105", 2),
+	})
+
+	tests := []struct {
+		name   string
+		update func()
+		period int64
+		want   []string
+	}{
+		{"rebuilt", func() {}, 2, []string{}},
+		{"rebuilt free", func() {}, 3, []string{"101", "105"}},
+		{"room freed", func() { index.Update(roomWithLessons(1, "101", 1)) }, 2, []string{"101"}},
+		{"room occupied", func() { index.Update(roomWithLessons(2, "105", 3)) }, 3, []string{"101"}},
+		{"room added", func() { index.Update(roomWithLessons(3, "012")) }, 3, []string{"012", "101"}},
+		{"room renamed", func() { index.Update(roomWithLessons(3, "204")) }, 3, []string{"101", "204"}},
+		{"room removed", func() { index.Remove(1) }, 3, []string{"204"}},
+		{"other periods kept", func() {}, 1, []string{"105", "204"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.update()
+			if got := freeDesignators(index, tt.period); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("free rooms in period %d: got %v, want %v", tt.period, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFloor(t *testing.T) {
+	tests := []struct {
+		designator string
+		floor      int64
+		ok         bool
+	}{
+		{"105", 1, true},
+		{"012", 0, true},
+		{"s.204", 2, true},
+		{"1105a", 11, true},
+		{"12", 0, false},
+		{"sala gimnastyczna", 0, false},
+	}
+
+	for _, tt := range tests {
+		floor, ok := Floor(tt.designator)
+		if floor != tt.floor || ok != tt.ok {
+			t.Errorf("Floor(%q) = %d, %v, want %d, %v", tt.designator, floor, ok, tt.floor, tt.ok)
+		}
+	}
+}
+
+func TestRoomsIndexHasLessons(t *testing.T) {
+	index := NewRoomsIndex()
+	index.Rebuild([]*models.Room{roomWithLessons(1, "101", 1)})
+
+	if !index.HasLessons(0) {
+		t.Error("monday: got no lessons, want lessons")
+	}
+	if index.HasLessons(5) {
+		t.Error("saturday: got lessons, want none")
+	}
+
+	index.Remove(1)
+	if index.HasLessons(0) {
+		t.Error("monday after removing the room: got lessons, want none")
+	}
+}
@@ -0,0 +1,55 @@
+// timetable/timetable.go
+package timetable
+
+import (
+	"fmt"
+	"time"
+
+	"smuggr.xyz/goptivum/common/models"
+)
+
+// Returns the minute of the day the timestamp points at
+func Minutes(timestamp *models.Timestamp) int64 {
+	if timestamp == nil {
+		return 0
+	}
+	return timestamp.Hour*60 + timestamp.Minute
+}
+
+// Returns the minute of the day of the given time
+func MinutesOf(t time.Time) int64 {
+	return int64(t.Hour()*60 + t.Minute())
+}
+
+// Returns the index of the schedule day of the given time (0 is monday)
+func DayIndex(t time.Time) int64 {
+	return int64((int(t.Weekday()) + 6) % 7)
+}
+
+// Parses a time in the HH:MM format into the minute of the day
+func ParseClock(s string) (int64, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time, expected HH:MM: %s", s)
+	}
+	return MinutesOf(t), nil
+}
+
+// Returns the period lasting at the given minute of the day, nil during breaks
+func PeriodAt(periods *models.Periods, minutes int64) *models.Period {
+	if periods == nil {
+		return nil
+	}
+
+	for _, period := range periods.Periods {
+		if period.TimeRange == nil {
+			continue
+		}
+
+		if Minutes(period.TimeRange.Start) <= minutes && minutes < Minutes(period.TimeRange.End) {
+			return period
+		}
+	}
+
+	return nil
+}